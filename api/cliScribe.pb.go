@@ -43,7 +43,7 @@ func (x Type) String() string {
 	return proto.EnumName(Type_name, int32(x))
 }
 func (Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_cliScribe_0316fe559e8937fb, []int{0}
+	return fileDescriptor_cliScribe_82cfa42e9d194e3c, []int{0}
 }
 
 type VersionRequest struct {
@@ -57,7 +57,7 @@ func (m *VersionRequest) Reset()         { *m = VersionRequest{} }
 func (m *VersionRequest) String() string { return proto.CompactTextString(m) }
 func (*VersionRequest) ProtoMessage()    {}
 func (*VersionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cliScribe_0316fe559e8937fb, []int{0}
+	return fileDescriptor_cliScribe_82cfa42e9d194e3c, []int{0}
 }
 func (m *VersionRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_VersionRequest.Unmarshal(m, b)
@@ -95,7 +95,7 @@ func (m *VersionResponse) Reset()         { *m = VersionResponse{} }
 func (m *VersionResponse) String() string { return proto.CompactTextString(m) }
 func (*VersionResponse) ProtoMessage()    {}
 func (*VersionResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cliScribe_0316fe559e8937fb, []int{1}
+	return fileDescriptor_cliScribe_82cfa42e9d194e3c, []int{1}
 }
 func (m *VersionResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_VersionResponse.Unmarshal(m, b)
@@ -135,7 +135,7 @@ func (m *Version) Reset()         { *m = Version{} }
 func (m *Version) String() string { return proto.CompactTextString(m) }
 func (*Version) ProtoMessage()    {}
 func (*Version) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cliScribe_0316fe559e8937fb, []int{2}
+	return fileDescriptor_cliScribe_82cfa42e9d194e3c, []int{2}
 }
 func (m *Version) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Version.Unmarshal(m, b)
@@ -186,7 +186,7 @@ func (m *StatsRequest) Reset()         { *m = StatsRequest{} }
 func (m *StatsRequest) String() string { return proto.CompactTextString(m) }
 func (*StatsRequest) ProtoMessage()    {}
 func (*StatsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cliScribe_0316fe559e8937fb, []int{3}
+	return fileDescriptor_cliScribe_82cfa42e9d194e3c, []int{3}
 }
 func (m *StatsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatsRequest.Unmarshal(m, b)
@@ -217,7 +217,7 @@ func (m *StatsResponse) Reset()         { *m = StatsResponse{} }
 func (m *StatsResponse) String() string { return proto.CompactTextString(m) }
 func (*StatsResponse) ProtoMessage()    {}
 func (*StatsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cliScribe_0316fe559e8937fb, []int{4}
+	return fileDescriptor_cliScribe_82cfa42e9d194e3c, []int{4}
 }
 func (m *StatsResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatsResponse.Unmarshal(m, b)
@@ -245,8 +245,22 @@ func (m *StatsResponse) GetResult() []*StatsResponse_Result {
 }
 
 type StatsResponse_Result struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Count                int64    `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Count int64  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	// degraded is set while the free space of the scribe is below its low watermark
+	Degraded bool `protobuf:"varint,3,opt,name=degraded,proto3" json:"degraded,omitempty"`
+	// free_bytes is the free space of the log root of the scribe
+	FreeBytes int64 `protobuf:"varint,4,opt,name=free_bytes,json=freeBytes,proto3" json:"free_bytes,omitempty"`
+	// shed is the number of requests dropped, or rejected, while degraded
+	Shed int64 `protobuf:"varint,5,opt,name=shed,proto3" json:"shed,omitempty"`
+	// failed is the number of requests failed to be written, even after retrying
+	Failed int64 `protobuf:"varint,6,opt,name=failed,proto3" json:"failed,omitempty"`
+	// retried is the number of failed writes retried
+	Retried int64 `protobuf:"varint,7,opt,name=retried,proto3" json:"retried,omitempty"`
+	// skipped is the number of failed requests dropped
+	Skipped int64 `protobuf:"varint,8,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	// dead_lettered is the number of failed requests written to the dead-letter file
+	DeadLettered         int64    `protobuf:"varint,9,opt,name=dead_lettered,json=deadLettered,proto3" json:"dead_lettered,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -256,7 +270,7 @@ func (m *StatsResponse_Result) Reset()         { *m = StatsResponse_Result{} }
 func (m *StatsResponse_Result) String() string { return proto.CompactTextString(m) }
 func (*StatsResponse_Result) ProtoMessage()    {}
 func (*StatsResponse_Result) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cliScribe_0316fe559e8937fb, []int{4, 0}
+	return fileDescriptor_cliScribe_82cfa42e9d194e3c, []int{4, 0}
 }
 func (m *StatsResponse_Result) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatsResponse_Result.Unmarshal(m, b)
@@ -290,6 +304,55 @@ func (m *StatsResponse_Result) GetCount() int64 {
 	return 0
 }
 
+func (m *StatsResponse_Result) GetDegraded() bool {
+	if m != nil {
+		return m.Degraded
+	}
+	return false
+}
+
+func (m *StatsResponse_Result) GetFreeBytes() int64 {
+	if m != nil {
+		return m.FreeBytes
+	}
+	return 0
+}
+
+func (m *StatsResponse_Result) GetShed() int64 {
+	if m != nil {
+		return m.Shed
+	}
+	return 0
+}
+
+func (m *StatsResponse_Result) GetFailed() int64 {
+	if m != nil {
+		return m.Failed
+	}
+	return 0
+}
+
+func (m *StatsResponse_Result) GetRetried() int64 {
+	if m != nil {
+		return m.Retried
+	}
+	return 0
+}
+
+func (m *StatsResponse_Result) GetSkipped() int64 {
+	if m != nil {
+		return m.Skipped
+	}
+	return 0
+}
+
+func (m *StatsResponse_Result) GetDeadLettered() int64 {
+	if m != nil {
+		return m.DeadLettered
+	}
+	return 0
+}
+
 type ResponsibilityRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -300,7 +363,7 @@ func (m *ResponsibilityRequest) Reset()         { *m = ResponsibilityRequest{} }
 func (m *ResponsibilityRequest) String() string { return proto.CompactTextString(m) }
 func (*ResponsibilityRequest) ProtoMessage()    {}
 func (*ResponsibilityRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cliScribe_0316fe559e8937fb, []int{5}
+	return fileDescriptor_cliScribe_82cfa42e9d194e3c, []int{5}
 }
 func (m *ResponsibilityRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ResponsibilityRequest.Unmarshal(m, b)
@@ -331,7 +394,7 @@ func (m *ResponsibilityResponse) Reset()         { *m = ResponsibilityResponse{}
 func (m *ResponsibilityResponse) String() string { return proto.CompactTextString(m) }
 func (*ResponsibilityResponse) ProtoMessage()    {}
 func (*ResponsibilityResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cliScribe_0316fe559e8937fb, []int{6}
+	return fileDescriptor_cliScribe_82cfa42e9d194e3c, []int{6}
 }
 func (m *ResponsibilityResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ResponsibilityResponse.Unmarshal(m, b)
@@ -370,7 +433,7 @@ func (m *ResponsibilityResponse_Result) Reset()         { *m = ResponsibilityRes
 func (m *ResponsibilityResponse_Result) String() string { return proto.CompactTextString(m) }
 func (*ResponsibilityResponse_Result) ProtoMessage()    {}
 func (*ResponsibilityResponse_Result) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cliScribe_0316fe559e8937fb, []int{6, 0}
+	return fileDescriptor_cliScribe_82cfa42e9d194e3c, []int{6, 0}
 }
 func (m *ResponsibilityResponse_Result) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ResponsibilityResponse_Result.Unmarshal(m, b)
@@ -404,6 +467,349 @@ func (m *ResponsibilityResponse_Result) GetResponsibility() string {
 	return ""
 }
 
+type ListFilesRequest struct {
+	// path_prefix limits the list to the files under that path
+	PathPrefix           string   `protobuf:"bytes,1,opt,name=path_prefix,json=pathPrefix,proto3" json:"path_prefix,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListFilesRequest) Reset()         { *m = ListFilesRequest{} }
+func (m *ListFilesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListFilesRequest) ProtoMessage()    {}
+func (*ListFilesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cliScribe_82cfa42e9d194e3c, []int{7}
+}
+func (m *ListFilesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListFilesRequest.Unmarshal(m, b)
+}
+func (m *ListFilesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListFilesRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListFilesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListFilesRequest.Merge(dst, src)
+}
+func (m *ListFilesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListFilesRequest.Size(m)
+}
+func (m *ListFilesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListFilesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListFilesRequest proto.InternalMessageInfo
+
+func (m *ListFilesRequest) GetPathPrefix() string {
+	if m != nil {
+		return m.PathPrefix
+	}
+	return ""
+}
+
+type ListFilesResponse struct {
+	Files                []*ListFilesResponse_File `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *ListFilesResponse) Reset()         { *m = ListFilesResponse{} }
+func (m *ListFilesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListFilesResponse) ProtoMessage()    {}
+func (*ListFilesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cliScribe_82cfa42e9d194e3c, []int{8}
+}
+func (m *ListFilesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListFilesResponse.Unmarshal(m, b)
+}
+func (m *ListFilesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListFilesResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListFilesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListFilesResponse.Merge(dst, src)
+}
+func (m *ListFilesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListFilesResponse.Size(m)
+}
+func (m *ListFilesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListFilesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListFilesResponse proto.InternalMessageInfo
+
+func (m *ListFilesResponse) GetFiles() []*ListFilesResponse_File {
+	if m != nil {
+		return m.Files
+	}
+	return nil
+}
+
+type ListFilesResponse_File struct {
+	Scribe   string `protobuf:"bytes,1,opt,name=scribe,proto3" json:"scribe,omitempty"`
+	Path     string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Filename string `protobuf:"bytes,3,opt,name=filename,proto3" json:"filename,omitempty"`
+	// size is the size in bytes of the current segment
+	Size int64 `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	// segments is the number of rotated segments
+	Segments int32 `protobuf:"varint,5,opt,name=segments,proto3" json:"segments,omitempty"`
+	// total_bytes is the size of all the segments
+	TotalBytes int64 `protobuf:"varint,6,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	// last_write is the time of the last write in nanoseconds since epoch
+	LastWrite            int64    `protobuf:"varint,7,opt,name=last_write,json=lastWrite,proto3" json:"last_write,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListFilesResponse_File) Reset()         { *m = ListFilesResponse_File{} }
+func (m *ListFilesResponse_File) String() string { return proto.CompactTextString(m) }
+func (*ListFilesResponse_File) ProtoMessage()    {}
+func (*ListFilesResponse_File) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cliScribe_82cfa42e9d194e3c, []int{8, 0}
+}
+func (m *ListFilesResponse_File) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListFilesResponse_File.Unmarshal(m, b)
+}
+func (m *ListFilesResponse_File) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListFilesResponse_File.Marshal(b, m, deterministic)
+}
+func (dst *ListFilesResponse_File) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListFilesResponse_File.Merge(dst, src)
+}
+func (m *ListFilesResponse_File) XXX_Size() int {
+	return xxx_messageInfo_ListFilesResponse_File.Size(m)
+}
+func (m *ListFilesResponse_File) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListFilesResponse_File.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListFilesResponse_File proto.InternalMessageInfo
+
+func (m *ListFilesResponse_File) GetScribe() string {
+	if m != nil {
+		return m.Scribe
+	}
+	return ""
+}
+
+func (m *ListFilesResponse_File) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ListFilesResponse_File) GetFilename() string {
+	if m != nil {
+		return m.Filename
+	}
+	return ""
+}
+
+func (m *ListFilesResponse_File) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *ListFilesResponse_File) GetSegments() int32 {
+	if m != nil {
+		return m.Segments
+	}
+	return 0
+}
+
+func (m *ListFilesResponse_File) GetTotalBytes() int64 {
+	if m != nil {
+		return m.TotalBytes
+	}
+	return 0
+}
+
+func (m *ListFilesResponse_File) GetLastWrite() int64 {
+	if m != nil {
+		return m.LastWrite
+	}
+	return 0
+}
+
+type RetentionRequest struct {
+	// run enforces the retention policy before reporting
+	Run                  bool     `protobuf:"varint,1,opt,name=run,proto3" json:"run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RetentionRequest) Reset()         { *m = RetentionRequest{} }
+func (m *RetentionRequest) String() string { return proto.CompactTextString(m) }
+func (*RetentionRequest) ProtoMessage()    {}
+func (*RetentionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cliScribe_82cfa42e9d194e3c, []int{9}
+}
+func (m *RetentionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetentionRequest.Unmarshal(m, b)
+}
+func (m *RetentionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetentionRequest.Marshal(b, m, deterministic)
+}
+func (dst *RetentionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetentionRequest.Merge(dst, src)
+}
+func (m *RetentionRequest) XXX_Size() int {
+	return xxx_messageInfo_RetentionRequest.Size(m)
+}
+func (m *RetentionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetentionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetentionRequest proto.InternalMessageInfo
+
+func (m *RetentionRequest) GetRun() bool {
+	if m != nil {
+		return m.Run
+	}
+	return false
+}
+
+type RetentionResponse struct {
+	// deletions are the latest deletions, oldest first
+	Deletions            []*RetentionResponse_Deletion `protobuf:"bytes,1,rep,name=deletions,proto3" json:"deletions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
+	XXX_unrecognized     []byte                        `json:"-"`
+	XXX_sizecache        int32                         `json:"-"`
+}
+
+func (m *RetentionResponse) Reset()         { *m = RetentionResponse{} }
+func (m *RetentionResponse) String() string { return proto.CompactTextString(m) }
+func (*RetentionResponse) ProtoMessage()    {}
+func (*RetentionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cliScribe_82cfa42e9d194e3c, []int{10}
+}
+func (m *RetentionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetentionResponse.Unmarshal(m, b)
+}
+func (m *RetentionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetentionResponse.Marshal(b, m, deterministic)
+}
+func (dst *RetentionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetentionResponse.Merge(dst, src)
+}
+func (m *RetentionResponse) XXX_Size() int {
+	return xxx_messageInfo_RetentionResponse.Size(m)
+}
+func (m *RetentionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetentionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetentionResponse proto.InternalMessageInfo
+
+func (m *RetentionResponse) GetDeletions() []*RetentionResponse_Deletion {
+	if m != nil {
+		return m.Deletions
+	}
+	return nil
+}
+
+type RetentionResponse_Deletion struct {
+	Scribe   string `protobuf:"bytes,1,opt,name=scribe,proto3" json:"scribe,omitempty"`
+	Path     string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Filename string `protobuf:"bytes,3,opt,name=filename,proto3" json:"filename,omitempty"`
+	// segment is the base name of the rotated file
+	Segment string `protobuf:"bytes,4,opt,name=segment,proto3" json:"segment,omitempty"`
+	Size    int64  `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	// reason is age, count or bytes
+	Reason string `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	// time of the deletion in nanoseconds since epoch
+	Time int64 `protobuf:"varint,7,opt,name=time,proto3" json:"time,omitempty"`
+	// dry_run is set when the segment was only reported
+	DryRun               bool     `protobuf:"varint,8,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RetentionResponse_Deletion) Reset()         { *m = RetentionResponse_Deletion{} }
+func (m *RetentionResponse_Deletion) String() string { return proto.CompactTextString(m) }
+func (*RetentionResponse_Deletion) ProtoMessage()    {}
+func (*RetentionResponse_Deletion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cliScribe_82cfa42e9d194e3c, []int{10, 0}
+}
+func (m *RetentionResponse_Deletion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetentionResponse_Deletion.Unmarshal(m, b)
+}
+func (m *RetentionResponse_Deletion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetentionResponse_Deletion.Marshal(b, m, deterministic)
+}
+func (dst *RetentionResponse_Deletion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetentionResponse_Deletion.Merge(dst, src)
+}
+func (m *RetentionResponse_Deletion) XXX_Size() int {
+	return xxx_messageInfo_RetentionResponse_Deletion.Size(m)
+}
+func (m *RetentionResponse_Deletion) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetentionResponse_Deletion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetentionResponse_Deletion proto.InternalMessageInfo
+
+func (m *RetentionResponse_Deletion) GetScribe() string {
+	if m != nil {
+		return m.Scribe
+	}
+	return ""
+}
+
+func (m *RetentionResponse_Deletion) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *RetentionResponse_Deletion) GetFilename() string {
+	if m != nil {
+		return m.Filename
+	}
+	return ""
+}
+
+func (m *RetentionResponse_Deletion) GetSegment() string {
+	if m != nil {
+		return m.Segment
+	}
+	return ""
+}
+
+func (m *RetentionResponse_Deletion) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *RetentionResponse_Deletion) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *RetentionResponse_Deletion) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *RetentionResponse_Deletion) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*VersionRequest)(nil), "com.romanostrechlis.scribe.api.VersionRequest")
 	proto.RegisterType((*VersionResponse)(nil), "com.romanostrechlis.scribe.api.VersionResponse")
@@ -414,6 +820,12 @@ func init() {
 	proto.RegisterType((*ResponsibilityRequest)(nil), "com.romanostrechlis.scribe.api.ResponsibilityRequest")
 	proto.RegisterType((*ResponsibilityResponse)(nil), "com.romanostrechlis.scribe.api.ResponsibilityResponse")
 	proto.RegisterType((*ResponsibilityResponse_Result)(nil), "com.romanostrechlis.scribe.api.ResponsibilityResponse.Result")
+	proto.RegisterType((*ListFilesRequest)(nil), "com.romanostrechlis.scribe.api.ListFilesRequest")
+	proto.RegisterType((*ListFilesResponse)(nil), "com.romanostrechlis.scribe.api.ListFilesResponse")
+	proto.RegisterType((*ListFilesResponse_File)(nil), "com.romanostrechlis.scribe.api.ListFilesResponse.File")
+	proto.RegisterType((*RetentionRequest)(nil), "com.romanostrechlis.scribe.api.RetentionRequest")
+	proto.RegisterType((*RetentionResponse)(nil), "com.romanostrechlis.scribe.api.RetentionResponse")
+	proto.RegisterType((*RetentionResponse_Deletion)(nil), "com.romanostrechlis.scribe.api.RetentionResponse.Deletion")
 	proto.RegisterEnum("com.romanostrechlis.scribe.api.Type", Type_name, Type_value)
 }
 
@@ -432,6 +844,8 @@ type CLIScribeClient interface {
 	GetVersion(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*VersionResponse, error)
 	GetStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
 	GetScribesResponsibility(ctx context.Context, in *ResponsibilityRequest, opts ...grpc.CallOption) (*ResponsibilityResponse, error)
+	ListFiles(ctx context.Context, in *ListFilesRequest, opts ...grpc.CallOption) (*ListFilesResponse, error)
+	GetRetention(ctx context.Context, in *RetentionRequest, opts ...grpc.CallOption) (*RetentionResponse, error)
 }
 
 type cLIScribeClient struct {
@@ -469,11 +883,31 @@ func (c *cLIScribeClient) GetScribesResponsibility(ctx context.Context, in *Resp
 	return out, nil
 }
 
+func (c *cLIScribeClient) ListFiles(ctx context.Context, in *ListFilesRequest, opts ...grpc.CallOption) (*ListFilesResponse, error) {
+	out := new(ListFilesResponse)
+	err := c.cc.Invoke(ctx, "/com.romanostrechlis.scribe.api.CLIScribe/ListFiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cLIScribeClient) GetRetention(ctx context.Context, in *RetentionRequest, opts ...grpc.CallOption) (*RetentionResponse, error) {
+	out := new(RetentionResponse)
+	err := c.cc.Invoke(ctx, "/com.romanostrechlis.scribe.api.CLIScribe/GetRetention", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CLIScribeServer is the server API for CLIScribe service.
 type CLIScribeServer interface {
 	GetVersion(context.Context, *VersionRequest) (*VersionResponse, error)
 	GetStats(context.Context, *StatsRequest) (*StatsResponse, error)
 	GetScribesResponsibility(context.Context, *ResponsibilityRequest) (*ResponsibilityResponse, error)
+	ListFiles(context.Context, *ListFilesRequest) (*ListFilesResponse, error)
+	GetRetention(context.Context, *RetentionRequest) (*RetentionResponse, error)
 }
 
 func RegisterCLIScribeServer(s *grpc.Server, srv CLIScribeServer) {
@@ -534,6 +968,42 @@ func _CLIScribe_GetScribesResponsibility_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CLIScribe_ListFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CLIScribeServer).ListFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/com.romanostrechlis.scribe.api.CLIScribe/ListFiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CLIScribeServer).ListFiles(ctx, req.(*ListFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CLIScribe_GetRetention_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetentionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CLIScribeServer).GetRetention(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/com.romanostrechlis.scribe.api.CLIScribe/GetRetention",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CLIScribeServer).GetRetention(ctx, req.(*RetentionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _CLIScribe_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "com.romanostrechlis.scribe.api.CLIScribe",
 	HandlerType: (*CLIScribeServer)(nil),
@@ -550,40 +1020,72 @@ var _CLIScribe_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetScribesResponsibility",
 			Handler:    _CLIScribe_GetScribesResponsibility_Handler,
 		},
+		{
+			MethodName: "ListFiles",
+			Handler:    _CLIScribe_ListFiles_Handler,
+		},
+		{
+			MethodName: "GetRetention",
+			Handler:    _CLIScribe_GetRetention_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "cliScribe.proto",
 }
 
-func init() { proto.RegisterFile("cliScribe.proto", fileDescriptor_cliScribe_0316fe559e8937fb) }
-
-var fileDescriptor_cliScribe_0316fe559e8937fb = []byte{
-	// 425 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x94, 0x4b, 0xab, 0xd3, 0x40,
-	0x14, 0xc7, 0x33, 0x37, 0xbd, 0x69, 0xef, 0xf1, 0x9a, 0x96, 0xc1, 0x47, 0xe8, 0x42, 0xc2, 0x20,
-	0x5a, 0x44, 0x23, 0xc4, 0x07, 0x6e, 0x5c, 0xf4, 0x45, 0x29, 0x54, 0x84, 0x69, 0x75, 0xe1, 0x2e,
-	0x0d, 0x03, 0x0e, 0xa4, 0x99, 0x34, 0x33, 0x15, 0xfa, 0x19, 0x5c, 0xf9, 0x61, 0xfc, 0x68, 0xee,
-	0xa5, 0x33, 0x93, 0x62, 0x83, 0xf4, 0x71, 0x77, 0x33, 0x27, 0xe7, 0xfc, 0xcf, 0xef, 0xe4, 0x7f,
-	0x18, 0x68, 0xa7, 0x19, 0x9f, 0xa7, 0x25, 0x5f, 0xb2, 0xa8, 0x28, 0x85, 0x12, 0xf8, 0x49, 0x2a,
-	0x56, 0x51, 0x29, 0x56, 0x49, 0x2e, 0xa4, 0x2a, 0x59, 0xfa, 0x3d, 0xe3, 0x32, 0x92, 0x26, 0x23,
-	0x29, 0x38, 0x21, 0xe0, 0x7f, 0x65, 0xa5, 0xe4, 0x22, 0xa7, 0x6c, 0xbd, 0x61, 0x52, 0xe1, 0x0e,
-	0xb8, 0x49, 0x96, 0x05, 0x28, 0x44, 0xbd, 0x16, 0xdd, 0x1d, 0xc9, 0x02, 0xda, 0xfb, 0x1c, 0x59,
-	0x88, 0x5c, 0x32, 0xdc, 0x87, 0x66, 0xc9, 0xe4, 0x26, 0x53, 0x32, 0x40, 0xa1, 0xdb, 0xbb, 0x17,
-	0x3f, 0x8f, 0x8e, 0x37, 0x8a, 0x2a, 0x85, 0xaa, 0x8e, 0xac, 0xa1, 0x69, 0x63, 0xf8, 0x03, 0x34,
-	0xd4, 0xb6, 0x60, 0xba, 0xa7, 0x1f, 0x3f, 0x3d, 0x25, 0xb5, 0xd8, 0x16, 0x8c, 0xea, 0x0a, 0x8c,
-	0xa1, 0x91, 0x27, 0x2b, 0x16, 0x5c, 0x85, 0xa8, 0x77, 0x43, 0xf5, 0x19, 0x07, 0xd0, 0xfc, 0x61,
-	0x84, 0x03, 0x57, 0x87, 0xab, 0x2b, 0xf1, 0xe1, 0x76, 0xae, 0x12, 0x25, 0xed, 0xa8, 0xe4, 0x17,
-	0x82, 0xfb, 0x36, 0x60, 0xe7, 0x9a, 0x81, 0x67, 0xf8, 0xec, 0x58, 0x6f, 0x4f, 0xb1, 0x1c, 0x94,
-	0x47, 0x54, 0xd7, 0x52, 0xab, 0xd1, 0x8d, 0xc1, 0x33, 0x91, 0x3d, 0x27, 0xfa, 0x87, 0xf3, 0x01,
-	0x5c, 0xa7, 0x62, 0x93, 0x2b, 0x0d, 0xef, 0x52, 0x73, 0x21, 0x8f, 0xe1, 0xa1, 0x95, 0xe3, 0x4b,
-	0x9e, 0x71, 0xb5, 0xad, 0x60, 0x7f, 0x23, 0x78, 0x54, 0xff, 0x62, 0xa9, 0xbf, 0xd4, 0xa8, 0x3f,
-	0x9e, 0xa2, 0xfe, 0xbf, 0x4e, 0x1d, 0x7f, 0x74, 0x14, 0xff, 0x19, 0xf8, 0xe5, 0x81, 0x8c, 0x35,
-	0xa1, 0x16, 0x7d, 0x11, 0x42, 0x63, 0x67, 0x18, 0xbe, 0x85, 0xd6, 0xa7, 0xf1, 0x68, 0xda, 0x5f,
-	0x7c, 0xa6, 0x1d, 0x07, 0x03, 0x78, 0xf3, 0x21, 0x9d, 0x0e, 0xc6, 0x1d, 0x14, 0xff, 0xb9, 0x82,
-	0x9b, 0xe1, 0x6c, 0x6a, 0xf6, 0x16, 0x0b, 0x80, 0x09, 0x53, 0xd5, 0x6a, 0x44, 0xe7, 0xee, 0x95,
-	0xf9, 0x4b, 0xdd, 0xd7, 0x67, 0xe7, 0x9b, 0x99, 0x89, 0x83, 0x39, 0xb4, 0x26, 0x4c, 0x69, 0x23,
-	0xf1, 0xcb, 0x33, 0xfd, 0x36, 0xcd, 0x5e, 0x5d, 0xb4, 0x1d, 0xc4, 0xc1, 0x3f, 0x11, 0x04, 0xbb,
-	0x5e, 0x3a, 0x43, 0x1e, 0xba, 0x80, 0xdf, 0x5d, 0xea, 0x9a, 0x81, 0x78, 0x7f, 0x37, 0xb3, 0x89,
-	0x33, 0xb8, 0xfe, 0xe6, 0x26, 0x05, 0x5f, 0x7a, 0xfa, 0xa5, 0x78, 0xf3, 0x37, 0x00, 0x00, 0xff,
-	0xff, 0xac, 0x74, 0x8f, 0x73, 0x3c, 0x04, 0x00, 0x00,
+func init() { proto.RegisterFile("cliScribe.proto", fileDescriptor_cliScribe_82cfa42e9d194e3c) }
+
+var fileDescriptor_cliScribe_82cfa42e9d194e3c = []byte{
+	// 811 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x5b, 0x6e, 0xf3, 0x44,
+	0x14, 0x8e, 0x73, 0xb5, 0xcf, 0x9f, 0x3f, 0x4d, 0x47, 0xd0, 0x5a, 0x91, 0x0a, 0x91, 0xa9, 0xa0,
+	0x42, 0x60, 0x68, 0x0b, 0x15, 0x42, 0xe2, 0xa1, 0x37, 0xaa, 0x4a, 0x41, 0xa0, 0x69, 0xb9, 0x88,
+	0x97, 0xc8, 0x89, 0x4f, 0xda, 0x11, 0x8e, 0xed, 0xce, 0x4c, 0x80, 0xb0, 0x05, 0x76, 0xc1, 0x1e,
+	0xfa, 0xca, 0x1b, 0x1b, 0x60, 0x05, 0xac, 0x81, 0x15, 0xa0, 0xb9, 0xd8, 0x24, 0x01, 0x35, 0x0d,
+	0xe2, 0x6d, 0xce, 0x37, 0xe7, 0x36, 0x9f, 0xcf, 0xf9, 0x12, 0xd8, 0x1a, 0x27, 0xec, 0x66, 0xcc,
+	0xd9, 0x08, 0xc3, 0x9c, 0x67, 0x32, 0x23, 0xaf, 0x8d, 0xb3, 0x69, 0xc8, 0xb3, 0x69, 0x94, 0x66,
+	0x42, 0x72, 0x1c, 0xdf, 0x27, 0x4c, 0x84, 0xc2, 0x78, 0x44, 0x39, 0x0b, 0x02, 0xe8, 0x7c, 0x85,
+	0x5c, 0xb0, 0x2c, 0xa5, 0xf8, 0x30, 0x43, 0x21, 0x49, 0x17, 0x6a, 0x51, 0x92, 0xf8, 0x4e, 0xdf,
+	0x39, 0x70, 0xa9, 0x3a, 0x06, 0xb7, 0xb0, 0x55, 0xfa, 0x88, 0x3c, 0x4b, 0x05, 0x92, 0x53, 0x68,
+	0x71, 0x14, 0xb3, 0x44, 0x0a, 0xdf, 0xe9, 0xd7, 0x0e, 0x5e, 0x1c, 0xbd, 0x15, 0x3e, 0x5d, 0x28,
+	0x2c, 0x32, 0x14, 0x71, 0xc1, 0x03, 0xb4, 0x2c, 0x46, 0x3e, 0x82, 0xba, 0x9c, 0xe7, 0xa8, 0x6b,
+	0x76, 0x8e, 0xf6, 0xd7, 0xa5, 0xba, 0x9d, 0xe7, 0x48, 0x75, 0x04, 0x21, 0x50, 0x4f, 0xa3, 0x29,
+	0xfa, 0xd5, 0xbe, 0x73, 0xe0, 0x51, 0x7d, 0x26, 0x3e, 0xb4, 0xbe, 0x37, 0x89, 0xfd, 0x9a, 0x86,
+	0x0b, 0x33, 0xe8, 0x40, 0xfb, 0x46, 0x46, 0x52, 0xd8, 0xa7, 0x06, 0xbf, 0x57, 0xe1, 0xa5, 0x05,
+	0xec, 0xbb, 0x06, 0xd0, 0x34, 0xfd, 0xd9, 0x67, 0x7d, 0xb0, 0xae, 0x97, 0xa5, 0xf0, 0x90, 0xea,
+	0x58, 0x6a, 0x73, 0xf4, 0xfe, 0x74, 0xa0, 0x69, 0xa0, 0xb2, 0x51, 0x67, 0xa1, 0xd1, 0x57, 0xa0,
+	0x31, 0xce, 0x66, 0xa9, 0xd4, 0xdd, 0xd7, 0xa8, 0x31, 0x48, 0x0f, 0xdc, 0x18, 0xef, 0x78, 0x14,
+	0x63, 0xac, 0xfb, 0x77, 0x69, 0x69, 0x93, 0x3d, 0x80, 0x09, 0x47, 0x1c, 0x8e, 0xe6, 0x12, 0x85,
+	0x5f, 0xd7, 0x61, 0x9e, 0x42, 0xce, 0x14, 0xa0, 0x8a, 0x88, 0x7b, 0x8c, 0xfd, 0x86, 0xbe, 0xd0,
+	0x67, 0xb2, 0x03, 0xcd, 0x49, 0xc4, 0x12, 0x8c, 0xfd, 0xa6, 0x46, 0xad, 0xa5, 0x58, 0xe2, 0x28,
+	0x39, 0xc3, 0xd8, 0x6f, 0xe9, 0x8b, 0xc2, 0x54, 0x37, 0xe2, 0x3b, 0x96, 0xe7, 0x18, 0xfb, 0xae,
+	0xb9, 0xb1, 0x26, 0x79, 0x03, 0x5e, 0xc6, 0x18, 0xc5, 0xc3, 0x04, 0xa5, 0x44, 0x8e, 0xb1, 0xef,
+	0xe9, 0xfb, 0xb6, 0x02, 0x07, 0x16, 0x0b, 0x76, 0xe1, 0x55, 0xcb, 0x07, 0x1b, 0xb1, 0x84, 0xc9,
+	0x79, 0xc1, 0xf6, 0xa3, 0x03, 0x3b, 0xab, 0x37, 0x96, 0xf6, 0x2f, 0x57, 0x68, 0xff, 0x64, 0x1d,
+	0xed, 0xff, 0x9e, 0x67, 0x95, 0xff, 0x8b, 0x27, 0xe9, 0x7f, 0x13, 0x3a, 0x7c, 0x29, 0x8d, 0x9d,
+	0xa2, 0x15, 0x34, 0x38, 0x86, 0xee, 0x80, 0x09, 0xf9, 0x29, 0x4b, 0xb0, 0x98, 0x1c, 0xf2, 0x3a,
+	0xbc, 0xc8, 0x23, 0x79, 0x3f, 0xcc, 0x39, 0x4e, 0xd8, 0x8f, 0x36, 0x2d, 0x28, 0xe8, 0x0b, 0x8d,
+	0x04, 0xbf, 0x54, 0x61, 0x7b, 0x21, 0xaa, 0x1c, 0xaf, 0xc6, 0x44, 0x01, 0xf6, 0x99, 0x27, 0xeb,
+	0x9e, 0xf9, 0x8f, 0x0c, 0xa1, 0xb2, 0xa8, 0x49, 0xd2, 0xfb, 0xd5, 0x81, 0xba, 0xb2, 0xd5, 0x37,
+	0x36, 0x41, 0xb6, 0x11, 0x6b, 0xa9, 0x57, 0xab, 0x96, 0x8a, 0xed, 0x50, 0x67, 0x35, 0x5e, 0x2a,
+	0x5a, 0xb3, 0x61, 0xd6, 0xa3, 0xb4, 0x95, 0xbf, 0x60, 0x3f, 0xa1, 0x1d, 0x2c, 0x7d, 0x56, 0xfe,
+	0x02, 0xef, 0xa6, 0x98, 0x4a, 0xa1, 0xe7, 0xaa, 0x41, 0x4b, 0x5b, 0xb1, 0x20, 0x33, 0x19, 0x25,
+	0x76, 0x1e, 0xcd, 0x80, 0x81, 0x86, 0xcc, 0x40, 0xee, 0x01, 0x24, 0x91, 0x90, 0xc3, 0x1f, 0x38,
+	0x93, 0x68, 0xe7, 0xcc, 0x53, 0xc8, 0xd7, 0x0a, 0x08, 0xf6, 0xa1, 0x4b, 0x51, 0x62, 0x2a, 0x97,
+	0xe5, 0x87, 0xcf, 0xd2, 0x42, 0x7e, 0xf8, 0x2c, 0x0d, 0x1e, 0xab, 0xb0, 0xbd, 0xe0, 0x66, 0xa9,
+	0xfc, 0x06, 0xbc, 0x18, 0x13, 0x54, 0x58, 0x41, 0xe7, 0xc7, 0xeb, 0xa7, 0x66, 0x25, 0x4b, 0x78,
+	0x61, 0x53, 0xd0, 0xbf, 0x93, 0xf5, 0x7e, 0x73, 0xc0, 0x2d, 0xf0, 0xff, 0x8d, 0x5a, 0xb5, 0x54,
+	0x86, 0x36, 0xcd, 0xae, 0x47, 0x0b, 0xb3, 0x24, 0xbd, 0xb1, 0x40, 0xfa, 0x8e, 0xda, 0x87, 0x48,
+	0x64, 0xa9, 0xe6, 0xd4, 0xa3, 0xd6, 0x52, 0xbe, 0x92, 0x4d, 0x0b, 0x26, 0xf5, 0x99, 0xec, 0x42,
+	0x2b, 0xe6, 0xf3, 0xa1, 0x22, 0xcd, 0xd5, 0xa4, 0x35, 0x63, 0x3e, 0xa7, 0xb3, 0xf4, 0xed, 0x3e,
+	0xd4, 0x95, 0x52, 0x92, 0x36, 0xb8, 0x9f, 0x5d, 0x5e, 0x5c, 0x9f, 0xde, 0x7e, 0x4e, 0xbb, 0x15,
+	0x02, 0xd0, 0xbc, 0x39, 0xa7, 0xd7, 0x67, 0x97, 0x5d, 0xe7, 0xe8, 0x8f, 0x3a, 0x78, 0xe7, 0x83,
+	0x6b, 0xf3, 0x83, 0x41, 0x32, 0x80, 0x2b, 0x94, 0x85, 0x26, 0x87, 0xcf, 0x15, 0x74, 0xf3, 0xdd,
+	0x7a, 0xef, 0x3d, 0xdb, 0xdf, 0x50, 0x1f, 0x54, 0x08, 0x03, 0xf7, 0x0a, 0xa5, 0x56, 0x50, 0xf2,
+	0xce, 0x33, 0x85, 0xd6, 0x14, 0x7b, 0x77, 0x23, 0x59, 0x0e, 0x2a, 0xe4, 0x67, 0x07, 0x7c, 0x55,
+	0x4b, 0x7b, 0x88, 0x65, 0xf5, 0x20, 0x1f, 0x6e, 0xaa, 0x36, 0xa6, 0x89, 0x93, 0xff, 0x26, 0x52,
+	0x41, 0x85, 0x70, 0xf0, 0xca, 0xcd, 0x26, 0xef, 0x6f, 0x20, 0x02, 0xa6, 0xf0, 0xe1, 0xc6, 0xb2,
+	0x11, 0x54, 0xc8, 0x0c, 0xda, 0x57, 0x28, 0xcb, 0x0d, 0x58, 0x5f, 0x76, 0x75, 0x33, 0x7b, 0x87,
+	0x1b, 0x44, 0x14, 0x65, 0xcf, 0x1a, 0xdf, 0xd6, 0xa2, 0x9c, 0x8d, 0x9a, 0xfa, 0xdf, 0xc8, 0xf1,
+	0x5f, 0x03, 0x00, 0x81, 0x39, 0x27, 0x09, 0xa0, 0x08, 0x00, 0x00,
 }
@@ -1,20 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // source: logScribe.proto
 
-/*
-Package api is a generated protocol buffer package.
-
-It is generated from these files:
-	logScribe.proto
-
-It has these top-level messages:
-	LogRequest
-	LogResponse
-	PingRequest
-	PingResponse
-	RegisterRequest
-	RegisterResponse
-*/
 package api
 
 import proto "github.com/golang/protobuf/proto"
@@ -37,20 +23,121 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+// Ack defines when the rpc server replies to a request
+type Ack int32
+
+const (
+	// ACK_DEFAULT uses the acknowledgement mode of the server
+	Ack_ACK_DEFAULT Ack = 0
+	// ACK_RECEIVED replies as soon as the request is accepted
+	Ack_ACK_RECEIVED Ack = 1
+	// ACK_WRITTEN replies after the line is written
+	Ack_ACK_WRITTEN Ack = 2
+	// ACK_SYNCED replies after the line is written and synced to disk
+	Ack_ACK_SYNCED Ack = 3
+)
+
+var Ack_name = map[int32]string{
+	0: "ACK_DEFAULT",
+	1: "ACK_RECEIVED",
+	2: "ACK_WRITTEN",
+	3: "ACK_SYNCED",
+}
+var Ack_value = map[string]int32{
+	"ACK_DEFAULT":  0,
+	"ACK_RECEIVED": 1,
+	"ACK_WRITTEN":  2,
+	"ACK_SYNCED":   3,
+}
+
+func (x Ack) String() string {
+	return proto.EnumName(Ack_name, int32(x))
+}
+func (Ack) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_logScribe_2d3ca41b3d7f0dfa, []int{0}
+}
+
+// Severity is the level of a log line
+type Severity int32
+
+const (
+	Severity_UNSPECIFIED Severity = 0
+	Severity_DEBUG       Severity = 1
+	Severity_INFO        Severity = 2
+	Severity_WARNING     Severity = 3
+	Severity_ERROR       Severity = 4
+	Severity_FATAL       Severity = 5
+)
+
+var Severity_name = map[int32]string{
+	0: "UNSPECIFIED",
+	1: "DEBUG",
+	2: "INFO",
+	3: "WARNING",
+	4: "ERROR",
+	5: "FATAL",
+}
+var Severity_value = map[string]int32{
+	"UNSPECIFIED": 0,
+	"DEBUG":       1,
+	"INFO":        2,
+	"WARNING":     3,
+	"ERROR":       4,
+	"FATAL":       5,
+}
+
+func (x Severity) String() string {
+	return proto.EnumName(Severity_name, int32(x))
+}
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_logScribe_2d3ca41b3d7f0dfa, []int{1}
+}
+
 // message is the structure that get serialized
 // the numbered fields are necessary for the serialization.
 // LogRequest is the structure that gets serialized
 // and then sent to rpc server.
 type LogRequest struct {
-	Filename string `protobuf:"bytes,1,opt,name=filename" json:"filename,omitempty"`
-	Path     string `protobuf:"bytes,2,opt,name=path" json:"path,omitempty"`
-	Line     string `protobuf:"bytes,3,opt,name=line" json:"line,omitempty"`
+	Filename string `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Path     string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Line     string `protobuf:"bytes,3,opt,name=line,proto3" json:"line,omitempty"`
+	// the following fields are optional and, when present,
+	// are rendered by the scribe's formatter along with the line.
+	Severity Severity `protobuf:"varint,4,opt,name=severity,proto3,enum=com.romanostrechlis.scribe.api.Severity" json:"severity,omitempty"`
+	// timestamp is the client's time in nanoseconds since epoch
+	Timestamp int64             `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Host      string            `protobuf:"bytes,6,opt,name=host,proto3" json:"host,omitempty"`
+	Fields    map[string]string `protobuf:"bytes,7,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// ack is the acknowledgement mode of the request
+	Ack                  Ack      `protobuf:"varint,8,opt,name=ack,proto3,enum=com.romanostrechlis.scribe.api.Ack" json:"ack,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *LogRequest) Reset()                    { *m = LogRequest{} }
-func (m *LogRequest) String() string            { return proto.CompactTextString(m) }
-func (*LogRequest) ProtoMessage()               {}
-func (*LogRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+func (m *LogRequest) Reset()         { *m = LogRequest{} }
+func (m *LogRequest) String() string { return proto.CompactTextString(m) }
+func (*LogRequest) ProtoMessage()    {}
+func (*LogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_logScribe_2d3ca41b3d7f0dfa, []int{0}
+}
+func (m *LogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogRequest.Unmarshal(m, b)
+}
+func (m *LogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogRequest.Marshal(b, m, deterministic)
+}
+func (dst *LogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogRequest.Merge(dst, src)
+}
+func (m *LogRequest) XXX_Size() int {
+	return xxx_messageInfo_LogRequest.Size(m)
+}
+func (m *LogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogRequest proto.InternalMessageInfo
 
 func (m *LogRequest) GetFilename() string {
 	if m != nil {
@@ -73,16 +160,76 @@ func (m *LogRequest) GetLine() string {
 	return ""
 }
 
-// LogResponse is the reply from rpc server
+func (m *LogRequest) GetSeverity() Severity {
+	if m != nil {
+		return m.Severity
+	}
+	return Severity_UNSPECIFIED
+}
+
+func (m *LogRequest) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *LogRequest) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *LogRequest) GetFields() map[string]string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
+func (m *LogRequest) GetAck() Ack {
+	if m != nil {
+		return m.Ack
+	}
+	return Ack_ACK_DEFAULT
+}
+
+// LogResponse is the reply from rpc server.
+// Failures are reported with gRPC status codes.
 type LogResponse struct {
-	Res string `protobuf:"bytes,1,opt,name=res" json:"res,omitempty"`
+	// res is always "true" and kept for older clients
+	Res                  string   `protobuf:"bytes,1,opt,name=res,proto3" json:"res,omitempty"` // Deprecated: Do not use.
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogResponse) Reset()         { *m = LogResponse{} }
+func (m *LogResponse) String() string { return proto.CompactTextString(m) }
+func (*LogResponse) ProtoMessage()    {}
+func (*LogResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_logScribe_2d3ca41b3d7f0dfa, []int{1}
+}
+func (m *LogResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogResponse.Unmarshal(m, b)
+}
+func (m *LogResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogResponse.Marshal(b, m, deterministic)
+}
+func (dst *LogResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogResponse.Merge(dst, src)
+}
+func (m *LogResponse) XXX_Size() int {
+	return xxx_messageInfo_LogResponse.Size(m)
+}
+func (m *LogResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogResponse.DiscardUnknown(m)
 }
 
-func (m *LogResponse) Reset()                    { *m = LogResponse{} }
-func (m *LogResponse) String() string            { return proto.CompactTextString(m) }
-func (*LogResponse) ProtoMessage()               {}
-func (*LogResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+var xxx_messageInfo_LogResponse proto.InternalMessageInfo
 
+// Deprecated: Do not use.
 func (m *LogResponse) GetRes() string {
 	if m != nil {
 		return m.Res
@@ -90,65 +237,588 @@ func (m *LogResponse) GetRes() string {
 	return ""
 }
 
-// PingRequest sends two numbers to mediator
-type PingRequest struct {
-	A          int32  `protobuf:"varint,1,opt,name=a" json:"a,omitempty"`
-	B          int32  `protobuf:"varint,2,opt,name=b" json:"b,omitempty"`
-	StreamerId string `protobuf:"bytes,3,opt,name=streamerId" json:"streamerId,omitempty"`
+// LogStreamSummary is the reply from rpc server
+// once a LogStream is closed by the client
+type LogStreamSummary struct {
+	Count                int64    `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *PingRequest) Reset()                    { *m = PingRequest{} }
-func (m *PingRequest) String() string            { return proto.CompactTextString(m) }
-func (*PingRequest) ProtoMessage()               {}
-func (*PingRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (m *LogStreamSummary) Reset()         { *m = LogStreamSummary{} }
+func (m *LogStreamSummary) String() string { return proto.CompactTextString(m) }
+func (*LogStreamSummary) ProtoMessage()    {}
+func (*LogStreamSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_logScribe_2d3ca41b3d7f0dfa, []int{2}
+}
+func (m *LogStreamSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogStreamSummary.Unmarshal(m, b)
+}
+func (m *LogStreamSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogStreamSummary.Marshal(b, m, deterministic)
+}
+func (dst *LogStreamSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogStreamSummary.Merge(dst, src)
+}
+func (m *LogStreamSummary) XXX_Size() int {
+	return xxx_messageInfo_LogStreamSummary.Size(m)
+}
+func (m *LogStreamSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogStreamSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogStreamSummary proto.InternalMessageInfo
 
-func (m *PingRequest) GetA() int32 {
+func (m *LogStreamSummary) GetCount() int64 {
 	if m != nil {
-		return m.A
+		return m.Count
 	}
 	return 0
 }
 
-func (m *PingRequest) GetB() int32 {
+// LogBatch holds many LogRequests sent together
+type LogBatch struct {
+	Entries []*LogRequest `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	// ack is the acknowledgement mode of the whole batch,
+	// the ack of each entry is ignored.
+	Ack                  Ack      `protobuf:"varint,2,opt,name=ack,proto3,enum=com.romanostrechlis.scribe.api.Ack" json:"ack,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogBatch) Reset()         { *m = LogBatch{} }
+func (m *LogBatch) String() string { return proto.CompactTextString(m) }
+func (*LogBatch) ProtoMessage()    {}
+func (*LogBatch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_logScribe_2d3ca41b3d7f0dfa, []int{3}
+}
+func (m *LogBatch) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogBatch.Unmarshal(m, b)
+}
+func (m *LogBatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogBatch.Marshal(b, m, deterministic)
+}
+func (dst *LogBatch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogBatch.Merge(dst, src)
+}
+func (m *LogBatch) XXX_Size() int {
+	return xxx_messageInfo_LogBatch.Size(m)
+}
+func (m *LogBatch) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogBatch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogBatch proto.InternalMessageInfo
+
+func (m *LogBatch) GetEntries() []*LogRequest {
 	if m != nil {
-		return m.B
+		return m.Entries
+	}
+	return nil
+}
+
+func (m *LogBatch) GetAck() Ack {
+	if m != nil {
+		return m.Ack
+	}
+	return Ack_ACK_DEFAULT
+}
+
+// LogBatchResponse is the reply from rpc server for a LogBatch
+type LogBatchResponse struct {
+	Count                int64    `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogBatchResponse) Reset()         { *m = LogBatchResponse{} }
+func (m *LogBatchResponse) String() string { return proto.CompactTextString(m) }
+func (*LogBatchResponse) ProtoMessage()    {}
+func (*LogBatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_logScribe_2d3ca41b3d7f0dfa, []int{4}
+}
+func (m *LogBatchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogBatchResponse.Unmarshal(m, b)
+}
+func (m *LogBatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogBatchResponse.Marshal(b, m, deterministic)
+}
+func (dst *LogBatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogBatchResponse.Merge(dst, src)
+}
+func (m *LogBatchResponse) XXX_Size() int {
+	return xxx_messageInfo_LogBatchResponse.Size(m)
+}
+func (m *LogBatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogBatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogBatchResponse proto.InternalMessageInfo
+
+func (m *LogBatchResponse) GetCount() int64 {
+	if m != nil {
+		return m.Count
 	}
 	return 0
 }
 
-func (m *PingRequest) GetStreamerId() string {
+// TailRequest asks for the lines of a file
+type TailRequest struct {
+	Filename string `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Path     string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// lines is the number of already written lines
+	// to send before following the file
+	Lines                int32    `protobuf:"varint,3,opt,name=lines,proto3" json:"lines,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TailRequest) Reset()         { *m = TailRequest{} }
+func (m *TailRequest) String() string { return proto.CompactTextString(m) }
+func (*TailRequest) ProtoMessage()    {}
+func (*TailRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_logScribe_2d3ca41b3d7f0dfa, []int{5}
+}
+func (m *TailRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TailRequest.Unmarshal(m, b)
+}
+func (m *TailRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TailRequest.Marshal(b, m, deterministic)
+}
+func (dst *TailRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TailRequest.Merge(dst, src)
+}
+func (m *TailRequest) XXX_Size() int {
+	return xxx_messageInfo_TailRequest.Size(m)
+}
+func (m *TailRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TailRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TailRequest proto.InternalMessageInfo
+
+func (m *TailRequest) GetFilename() string {
 	if m != nil {
-		return m.StreamerId
+		return m.Filename
 	}
 	return ""
 }
 
-// PingResponse returns the mediator's response
-type PingResponse struct {
-	Res int32 `protobuf:"varint,2,opt,name=res" json:"res,omitempty"`
+func (m *TailRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *TailRequest) GetLines() int32 {
+	if m != nil {
+		return m.Lines
+	}
+	return 0
+}
+
+// LogLine is a line read from a file
+type LogLine struct {
+	Filename             string   `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Path                 string   `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Line                 string   `protobuf:"bytes,3,opt,name=line,proto3" json:"line,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogLine) Reset()         { *m = LogLine{} }
+func (m *LogLine) String() string { return proto.CompactTextString(m) }
+func (*LogLine) ProtoMessage()    {}
+func (*LogLine) Descriptor() ([]byte, []int) {
+	return fileDescriptor_logScribe_2d3ca41b3d7f0dfa, []int{6}
+}
+func (m *LogLine) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogLine.Unmarshal(m, b)
+}
+func (m *LogLine) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogLine.Marshal(b, m, deterministic)
+}
+func (dst *LogLine) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogLine.Merge(dst, src)
+}
+func (m *LogLine) XXX_Size() int {
+	return xxx_messageInfo_LogLine.Size(m)
+}
+func (m *LogLine) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogLine.DiscardUnknown(m)
 }
 
-func (m *PingResponse) Reset()                    { *m = PingResponse{} }
-func (m *PingResponse) String() string            { return proto.CompactTextString(m) }
-func (*PingResponse) ProtoMessage()               {}
-func (*PingResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+var xxx_messageInfo_LogLine proto.InternalMessageInfo
 
-func (m *PingResponse) GetRes() int32 {
+func (m *LogLine) GetFilename() string {
 	if m != nil {
-		return m.Res
+		return m.Filename
+	}
+	return ""
+}
+
+func (m *LogLine) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *LogLine) GetLine() string {
+	if m != nil {
+		return m.Line
+	}
+	return ""
+}
+
+// ReadRequest asks for a page of the lines of a file
+type ReadRequest struct {
+	Filename string `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Path     string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// offset is the number of lines to skip
+	Offset int64 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	// limit is the maximum number of lines returned,
+	// zero returns the default number of lines
+	Limit int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	// from and to, in nanoseconds since epoch, select only the lines
+	// written during that time range, zero means unbounded. Lines of the
+	// json and text formats are selected by their own time, while lines
+	// without one, i.e. of the raw format, by the time of their segment
+	From                 int64    `protobuf:"varint,5,opt,name=from,proto3" json:"from,omitempty"`
+	To                   int64    `protobuf:"varint,6,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadRequest) Reset()         { *m = ReadRequest{} }
+func (m *ReadRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadRequest) ProtoMessage()    {}
+func (*ReadRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_logScribe_2d3ca41b3d7f0dfa, []int{7}
+}
+func (m *ReadRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadRequest.Unmarshal(m, b)
+}
+func (m *ReadRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadRequest.Marshal(b, m, deterministic)
+}
+func (dst *ReadRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadRequest.Merge(dst, src)
+}
+func (m *ReadRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadRequest.Size(m)
+}
+func (m *ReadRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadRequest proto.InternalMessageInfo
+
+func (m *ReadRequest) GetFilename() string {
+	if m != nil {
+		return m.Filename
+	}
+	return ""
+}
+
+func (m *ReadRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *ReadRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *ReadRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ReadRequest) GetFrom() int64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *ReadRequest) GetTo() int64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+// ReadResponse holds a page of lines
+type ReadResponse struct {
+	Lines []*LogLine `protobuf:"bytes,1,rep,name=lines,proto3" json:"lines,omitempty"`
+	// next_offset is the offset of the next page
+	NextOffset int64 `protobuf:"varint,2,opt,name=next_offset,json=nextOffset,proto3" json:"next_offset,omitempty"`
+	// more is true when there are lines after this page
+	More                 bool     `protobuf:"varint,3,opt,name=more,proto3" json:"more,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadResponse) Reset()         { *m = ReadResponse{} }
+func (m *ReadResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadResponse) ProtoMessage()    {}
+func (*ReadResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_logScribe_2d3ca41b3d7f0dfa, []int{8}
+}
+func (m *ReadResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadResponse.Unmarshal(m, b)
+}
+func (m *ReadResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadResponse.Marshal(b, m, deterministic)
+}
+func (dst *ReadResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadResponse.Merge(dst, src)
+}
+func (m *ReadResponse) XXX_Size() int {
+	return xxx_messageInfo_ReadResponse.Size(m)
+}
+func (m *ReadResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadResponse proto.InternalMessageInfo
+
+func (m *ReadResponse) GetLines() []*LogLine {
+	if m != nil {
+		return m.Lines
+	}
+	return nil
+}
+
+func (m *ReadResponse) GetNextOffset() int64 {
+	if m != nil {
+		return m.NextOffset
+	}
+	return 0
+}
+
+func (m *ReadResponse) GetMore() bool {
+	if m != nil {
+		return m.More
+	}
+	return false
+}
+
+// SearchRequest is a query over the lines of the files
+type SearchRequest struct {
+	// query is matched against every line, as a substring
+	// or, when regex is true, as a regular expression
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Regex bool   `protobuf:"varint,2,opt,name=regex,proto3" json:"regex,omitempty"`
+	// path_prefix limits the search to the files under that path
+	PathPrefix string `protobuf:"bytes,3,opt,name=path_prefix,json=pathPrefix,proto3" json:"path_prefix,omitempty"`
+	// from and to, in nanoseconds since epoch, select only the lines
+	// written during that time range, as they do for a ReadRequest
+	From int64 `protobuf:"varint,4,opt,name=from,proto3" json:"from,omitempty"`
+	To   int64 `protobuf:"varint,5,opt,name=to,proto3" json:"to,omitempty"`
+	// limit is the maximum number of results,
+	// zero returns the default number of results
+	Limit                int32    `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchRequest) Reset()         { *m = SearchRequest{} }
+func (m *SearchRequest) String() string { return proto.CompactTextString(m) }
+func (*SearchRequest) ProtoMessage()    {}
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_logScribe_2d3ca41b3d7f0dfa, []int{9}
+}
+func (m *SearchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchRequest.Unmarshal(m, b)
+}
+func (m *SearchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchRequest.Marshal(b, m, deterministic)
+}
+func (dst *SearchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchRequest.Merge(dst, src)
+}
+func (m *SearchRequest) XXX_Size() int {
+	return xxx_messageInfo_SearchRequest.Size(m)
+}
+func (m *SearchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchRequest proto.InternalMessageInfo
+
+func (m *SearchRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
+}
+
+func (m *SearchRequest) GetRegex() bool {
+	if m != nil {
+		return m.Regex
+	}
+	return false
+}
+
+func (m *SearchRequest) GetPathPrefix() string {
+	if m != nil {
+		return m.PathPrefix
+	}
+	return ""
+}
+
+func (m *SearchRequest) GetFrom() int64 {
+	if m != nil {
+		return m.From
+	}
+	return 0
+}
+
+func (m *SearchRequest) GetTo() int64 {
+	if m != nil {
+		return m.To
+	}
+	return 0
+}
+
+func (m *SearchRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+// SearchResult is a line matching a query
+type SearchResult struct {
+	// scribe is the id of the scribe holding the line
+	Scribe   string `protobuf:"bytes,1,opt,name=scribe,proto3" json:"scribe,omitempty"`
+	Filename string `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	Path     string `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	// segment is the name of the file holding the line
+	Segment string `protobuf:"bytes,4,opt,name=segment,proto3" json:"segment,omitempty"`
+	// line_number is the number of the line in the segment, starting from 1
+	LineNumber           int64    `protobuf:"varint,5,opt,name=line_number,json=lineNumber,proto3" json:"line_number,omitempty"`
+	Line                 string   `protobuf:"bytes,6,opt,name=line,proto3" json:"line,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchResult) Reset()         { *m = SearchResult{} }
+func (m *SearchResult) String() string { return proto.CompactTextString(m) }
+func (*SearchResult) ProtoMessage()    {}
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_logScribe_2d3ca41b3d7f0dfa, []int{10}
+}
+func (m *SearchResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchResult.Unmarshal(m, b)
+}
+func (m *SearchResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchResult.Marshal(b, m, deterministic)
+}
+func (dst *SearchResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchResult.Merge(dst, src)
+}
+func (m *SearchResult) XXX_Size() int {
+	return xxx_messageInfo_SearchResult.Size(m)
+}
+func (m *SearchResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchResult proto.InternalMessageInfo
+
+func (m *SearchResult) GetScribe() string {
+	if m != nil {
+		return m.Scribe
+	}
+	return ""
+}
+
+func (m *SearchResult) GetFilename() string {
+	if m != nil {
+		return m.Filename
+	}
+	return ""
+}
+
+func (m *SearchResult) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *SearchResult) GetSegment() string {
+	if m != nil {
+		return m.Segment
+	}
+	return ""
+}
+
+func (m *SearchResult) GetLineNumber() int64 {
+	if m != nil {
+		return m.LineNumber
 	}
 	return 0
 }
 
+func (m *SearchResult) GetLine() string {
+	if m != nil {
+		return m.Line
+	}
+	return ""
+}
+
 type RegisterRequest struct {
-	Id   string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
-	Addr string `protobuf:"bytes,2,opt,name=addr" json:"addr,omitempty"`
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Addr                 string   `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *RegisterRequest) Reset()                    { *m = RegisterRequest{} }
-func (m *RegisterRequest) String() string            { return proto.CompactTextString(m) }
-func (*RegisterRequest) ProtoMessage()               {}
-func (*RegisterRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (m *RegisterRequest) Reset()         { *m = RegisterRequest{} }
+func (m *RegisterRequest) String() string { return proto.CompactTextString(m) }
+func (*RegisterRequest) ProtoMessage()    {}
+func (*RegisterRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_logScribe_2d3ca41b3d7f0dfa, []int{11}
+}
+func (m *RegisterRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterRequest.Unmarshal(m, b)
+}
+func (m *RegisterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterRequest.Marshal(b, m, deterministic)
+}
+func (dst *RegisterRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterRequest.Merge(dst, src)
+}
+func (m *RegisterRequest) XXX_Size() int {
+	return xxx_messageInfo_RegisterRequest.Size(m)
+}
+func (m *RegisterRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterRequest proto.InternalMessageInfo
 
 func (m *RegisterRequest) GetId() string {
 	if m != nil {
@@ -164,15 +834,41 @@ func (m *RegisterRequest) GetAddr() string {
 	return ""
 }
 
+// RegisterResponse is the reply of a successful registration.
+// Failures are reported with gRPC status codes.
 type RegisterResponse struct {
-	Res string `protobuf:"bytes,1,opt,name=res" json:"res,omitempty"`
+	// res is always "Success" and kept for older clients
+	Res                  string   `protobuf:"bytes,1,opt,name=res,proto3" json:"res,omitempty"` // Deprecated: Do not use.
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *RegisterResponse) Reset()                    { *m = RegisterResponse{} }
-func (m *RegisterResponse) String() string            { return proto.CompactTextString(m) }
-func (*RegisterResponse) ProtoMessage()               {}
-func (*RegisterResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (m *RegisterResponse) Reset()         { *m = RegisterResponse{} }
+func (m *RegisterResponse) String() string { return proto.CompactTextString(m) }
+func (*RegisterResponse) ProtoMessage()    {}
+func (*RegisterResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_logScribe_2d3ca41b3d7f0dfa, []int{12}
+}
+func (m *RegisterResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterResponse.Unmarshal(m, b)
+}
+func (m *RegisterResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterResponse.Marshal(b, m, deterministic)
+}
+func (dst *RegisterResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterResponse.Merge(dst, src)
+}
+func (m *RegisterResponse) XXX_Size() int {
+	return xxx_messageInfo_RegisterResponse.Size(m)
+}
+func (m *RegisterResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterResponse proto.InternalMessageInfo
 
+// Deprecated: Do not use.
 func (m *RegisterResponse) GetRes() string {
 	if m != nil {
 		return m.Res
@@ -182,11 +878,21 @@ func (m *RegisterResponse) GetRes() string {
 
 func init() {
 	proto.RegisterType((*LogRequest)(nil), "com.romanostrechlis.scribe.api.LogRequest")
+	proto.RegisterMapType((map[string]string)(nil), "com.romanostrechlis.scribe.api.LogRequest.FieldsEntry")
 	proto.RegisterType((*LogResponse)(nil), "com.romanostrechlis.scribe.api.LogResponse")
-	proto.RegisterType((*PingRequest)(nil), "com.romanostrechlis.scribe.api.PingRequest")
-	proto.RegisterType((*PingResponse)(nil), "com.romanostrechlis.scribe.api.PingResponse")
+	proto.RegisterType((*LogStreamSummary)(nil), "com.romanostrechlis.scribe.api.LogStreamSummary")
+	proto.RegisterType((*LogBatch)(nil), "com.romanostrechlis.scribe.api.LogBatch")
+	proto.RegisterType((*LogBatchResponse)(nil), "com.romanostrechlis.scribe.api.LogBatchResponse")
+	proto.RegisterType((*TailRequest)(nil), "com.romanostrechlis.scribe.api.TailRequest")
+	proto.RegisterType((*LogLine)(nil), "com.romanostrechlis.scribe.api.LogLine")
+	proto.RegisterType((*ReadRequest)(nil), "com.romanostrechlis.scribe.api.ReadRequest")
+	proto.RegisterType((*ReadResponse)(nil), "com.romanostrechlis.scribe.api.ReadResponse")
+	proto.RegisterType((*SearchRequest)(nil), "com.romanostrechlis.scribe.api.SearchRequest")
+	proto.RegisterType((*SearchResult)(nil), "com.romanostrechlis.scribe.api.SearchResult")
 	proto.RegisterType((*RegisterRequest)(nil), "com.romanostrechlis.scribe.api.RegisterRequest")
 	proto.RegisterType((*RegisterResponse)(nil), "com.romanostrechlis.scribe.api.RegisterResponse")
+	proto.RegisterEnum("com.romanostrechlis.scribe.api.Ack", Ack_name, Ack_value)
+	proto.RegisterEnum("com.romanostrechlis.scribe.api.Severity", Severity_name, Severity_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -197,12 +903,19 @@ var _ grpc.ClientConn
 // is compatible with the grpc package it is being compiled against.
 const _ = grpc.SupportPackageIsVersion4
 
-// Client API for LogScribe service
-
+// LogScribeClient is the client API for LogScribe service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type LogScribeClient interface {
 	// the LogScribe service sends a LogRequest
 	// and recieves a LogResponse
 	Log(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (*LogResponse, error)
+	// LogStream receives a stream of LogRequests
+	// and replies with a LogStreamSummary when the client closes it
+	LogStream(ctx context.Context, opts ...grpc.CallOption) (LogScribe_LogStreamClient, error)
+	// LogBatch delivers many LogRequests, for any number
+	// of files, in a single call
+	LogBatch(ctx context.Context, in *LogBatch, opts ...grpc.CallOption) (*LogBatchResponse, error)
 }
 
 type logScribeClient struct {
@@ -215,19 +928,67 @@ func NewLogScribeClient(cc *grpc.ClientConn) LogScribeClient {
 
 func (c *logScribeClient) Log(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (*LogResponse, error) {
 	out := new(LogResponse)
-	err := grpc.Invoke(ctx, "/com.romanostrechlis.scribe.api.LogScribe/Log", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/com.romanostrechlis.scribe.api.LogScribe/Log", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// Server API for LogScribe service
+func (c *logScribeClient) LogStream(ctx context.Context, opts ...grpc.CallOption) (LogScribe_LogStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LogScribe_serviceDesc.Streams[0], "/com.romanostrechlis.scribe.api.LogScribe/LogStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &logScribeLogStreamClient{stream}
+	return x, nil
+}
+
+type LogScribe_LogStreamClient interface {
+	Send(*LogRequest) error
+	CloseAndRecv() (*LogStreamSummary, error)
+	grpc.ClientStream
+}
 
+type logScribeLogStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *logScribeLogStreamClient) Send(m *LogRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *logScribeLogStreamClient) CloseAndRecv() (*LogStreamSummary, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(LogStreamSummary)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *logScribeClient) LogBatch(ctx context.Context, in *LogBatch, opts ...grpc.CallOption) (*LogBatchResponse, error) {
+	out := new(LogBatchResponse)
+	err := c.cc.Invoke(ctx, "/com.romanostrechlis.scribe.api.LogScribe/LogBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LogScribeServer is the server API for LogScribe service.
 type LogScribeServer interface {
 	// the LogScribe service sends a LogRequest
 	// and recieves a LogResponse
 	Log(context.Context, *LogRequest) (*LogResponse, error)
+	// LogStream receives a stream of LogRequests
+	// and replies with a LogStreamSummary when the client closes it
+	LogStream(LogScribe_LogStreamServer) error
+	// LogBatch delivers many LogRequests, for any number
+	// of files, in a single call
+	LogBatch(context.Context, *LogBatch) (*LogBatchResponse, error)
 }
 
 func RegisterLogScribeServer(s *grpc.Server, srv LogScribeServer) {
@@ -252,6 +1013,50 @@ func _LogScribe_Log_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LogScribe_LogStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LogScribeServer).LogStream(&logScribeLogStreamServer{stream})
+}
+
+type LogScribe_LogStreamServer interface {
+	SendAndClose(*LogStreamSummary) error
+	Recv() (*LogRequest, error)
+	grpc.ServerStream
+}
+
+type logScribeLogStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *logScribeLogStreamServer) SendAndClose(m *LogStreamSummary) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *logScribeLogStreamServer) Recv() (*LogRequest, error) {
+	m := new(LogRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _LogScribe_LogBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogBatch)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogScribeServer).LogBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/com.romanostrechlis.scribe.api.LogScribe/LogBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogScribeServer).LogBatch(ctx, req.(*LogBatch))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _LogScribe_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "com.romanostrechlis.scribe.api.LogScribe",
 	HandlerType: (*LogScribeServer)(nil),
@@ -260,77 +1065,223 @@ var _LogScribe_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Log",
 			Handler:    _LogScribe_Log_Handler,
 		},
+		{
+			MethodName: "LogBatch",
+			Handler:    _LogScribe_LogBatch_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "LogStream",
+			Handler:       _LogScribe_LogStream_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "logScribe.proto",
 }
 
-// Client API for Pinger service
-
-type PingerClient interface {
-	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
+// LogReaderClient is the client API for LogReader service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type LogReaderClient interface {
+	// Tail streams the lines of a file as they get written,
+	// optionally starting with the last lines already written
+	Tail(ctx context.Context, in *TailRequest, opts ...grpc.CallOption) (LogReader_TailClient, error)
+	// Read returns a page of the lines of a file across
+	// its rotated and current segments, oldest first
+	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error)
+	// Search streams the lines matching a query, ordered by file, segment
+	// and line. Scribes search their own files while a mediator fans the
+	// query out to every registered scribe, merging the results by file
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (LogReader_SearchClient, error)
 }
 
-type pingerClient struct {
+type logReaderClient struct {
 	cc *grpc.ClientConn
 }
 
-func NewPingerClient(cc *grpc.ClientConn) PingerClient {
-	return &pingerClient{cc}
+func NewLogReaderClient(cc *grpc.ClientConn) LogReaderClient {
+	return &logReaderClient{cc}
 }
 
-func (c *pingerClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error) {
-	out := new(PingResponse)
-	err := grpc.Invoke(ctx, "/com.romanostrechlis.scribe.api.Pinger/Ping", in, out, c.cc, opts...)
+func (c *logReaderClient) Tail(ctx context.Context, in *TailRequest, opts ...grpc.CallOption) (LogReader_TailClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LogReader_serviceDesc.Streams[0], "/com.romanostrechlis.scribe.api.LogReader/Tail", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &logReaderTailClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LogReader_TailClient interface {
+	Recv() (*LogLine, error)
+	grpc.ClientStream
+}
+
+type logReaderTailClient struct {
+	grpc.ClientStream
+}
+
+func (x *logReaderTailClient) Recv() (*LogLine, error) {
+	m := new(LogLine)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *logReaderClient) Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error) {
+	out := new(ReadResponse)
+	err := c.cc.Invoke(ctx, "/com.romanostrechlis.scribe.api.LogReader/Read", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// Server API for Pinger service
+func (c *logReaderClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (LogReader_SearchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LogReader_serviceDesc.Streams[1], "/com.romanostrechlis.scribe.api.LogReader/Search", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &logReaderSearchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LogReader_SearchClient interface {
+	Recv() (*SearchResult, error)
+	grpc.ClientStream
+}
+
+type logReaderSearchClient struct {
+	grpc.ClientStream
+}
+
+func (x *logReaderSearchClient) Recv() (*SearchResult, error) {
+	m := new(SearchResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// LogReaderServer is the server API for LogReader service.
+type LogReaderServer interface {
+	// Tail streams the lines of a file as they get written,
+	// optionally starting with the last lines already written
+	Tail(*TailRequest, LogReader_TailServer) error
+	// Read returns a page of the lines of a file across
+	// its rotated and current segments, oldest first
+	Read(context.Context, *ReadRequest) (*ReadResponse, error)
+	// Search streams the lines matching a query, ordered by file, segment
+	// and line. Scribes search their own files while a mediator fans the
+	// query out to every registered scribe, merging the results by file
+	Search(*SearchRequest, LogReader_SearchServer) error
+}
+
+func RegisterLogReaderServer(s *grpc.Server, srv LogReaderServer) {
+	s.RegisterService(&_LogReader_serviceDesc, srv)
+}
 
-type PingerServer interface {
-	Ping(context.Context, *PingRequest) (*PingResponse, error)
+func _LogReader_Tail_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LogReaderServer).Tail(m, &logReaderTailServer{stream})
 }
 
-func RegisterPingerServer(s *grpc.Server, srv PingerServer) {
-	s.RegisterService(&_Pinger_serviceDesc, srv)
+type LogReader_TailServer interface {
+	Send(*LogLine) error
+	grpc.ServerStream
 }
 
-func _Pinger_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PingRequest)
+type logReaderTailServer struct {
+	grpc.ServerStream
+}
+
+func (x *logReaderTailServer) Send(m *LogLine) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _LogReader_Read_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(PingerServer).Ping(ctx, in)
+		return srv.(LogReaderServer).Read(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/com.romanostrechlis.scribe.api.Pinger/Ping",
+		FullMethod: "/com.romanostrechlis.scribe.api.LogReader/Read",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(PingerServer).Ping(ctx, req.(*PingRequest))
+		return srv.(LogReaderServer).Read(ctx, req.(*ReadRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _Pinger_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "com.romanostrechlis.scribe.api.Pinger",
-	HandlerType: (*PingerServer)(nil),
+func _LogReader_Search_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LogReaderServer).Search(m, &logReaderSearchServer{stream})
+}
+
+type LogReader_SearchServer interface {
+	Send(*SearchResult) error
+	grpc.ServerStream
+}
+
+type logReaderSearchServer struct {
+	grpc.ServerStream
+}
+
+func (x *logReaderSearchServer) Send(m *SearchResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _LogReader_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "com.romanostrechlis.scribe.api.LogReader",
+	HandlerType: (*LogReaderServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "Ping",
-			Handler:    _Pinger_Ping_Handler,
+			MethodName: "Read",
+			Handler:    _LogReader_Read_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Tail",
+			Handler:       _LogReader_Tail_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Search",
+			Handler:       _LogReader_Search_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "logScribe.proto",
 }
 
-// Client API for Register service
-
+// RegisterClient is the client API for Register service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type RegisterClient interface {
 	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
 }
@@ -345,15 +1296,14 @@ func NewRegisterClient(cc *grpc.ClientConn) RegisterClient {
 
 func (c *registerClient) Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error) {
 	out := new(RegisterResponse)
-	err := grpc.Invoke(ctx, "/com.romanostrechlis.scribe.api.Register/Register", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/com.romanostrechlis.scribe.api.Register/Register", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// Server API for Register service
-
+// RegisterServer is the server API for Register service.
 type RegisterServer interface {
 	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
 }
@@ -393,29 +1343,67 @@ var _Register_serviceDesc = grpc.ServiceDesc{
 	Metadata: "logScribe.proto",
 }
 
-func init() { proto.RegisterFile("logScribe.proto", fileDescriptor0) }
-
-var fileDescriptor0 = []byte{
-	// 323 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0x4f, 0x4f, 0xb3, 0x40,
-	0x10, 0xc6, 0x5f, 0x4a, 0xdb, 0xb4, 0xd3, 0xe6, 0x6d, 0xb3, 0x27, 0xc2, 0xa1, 0x36, 0x1b, 0x0f,
-	0x46, 0x0d, 0x9a, 0x1a, 0xbf, 0x80, 0xb7, 0x26, 0x1c, 0x1a, 0xbc, 0x79, 0x72, 0x81, 0x91, 0x6e,
-	0xc2, 0xb2, 0xb8, 0x8b, 0x27, 0xbf, 0xbc, 0xd9, 0xe5, 0x4f, 0xd1, 0x44, 0xcb, 0xed, 0xd9, 0x61,
-	0xe6, 0xf9, 0xcd, 0x3c, 0x01, 0x56, 0xb9, 0xcc, 0x9e, 0x13, 0xc5, 0x63, 0x0c, 0x4a, 0x25, 0x2b,
-	0x49, 0x36, 0x89, 0x14, 0x81, 0x92, 0x82, 0x15, 0x52, 0x57, 0x0a, 0x93, 0x63, 0xce, 0x75, 0xa0,
-	0xeb, 0x0e, 0x56, 0x72, 0x7a, 0x00, 0x08, 0x65, 0x16, 0xe1, 0xfb, 0x07, 0xea, 0x8a, 0xf8, 0x30,
-	0x7b, 0xe3, 0x39, 0x16, 0x4c, 0xa0, 0xe7, 0x6c, 0x9d, 0xab, 0x79, 0xd4, 0xbd, 0x09, 0x81, 0x71,
-	0xc9, 0xaa, 0xa3, 0x37, 0xb2, 0x75, 0xab, 0x4d, 0x2d, 0xe7, 0x05, 0x7a, 0x6e, 0x5d, 0x33, 0x9a,
-	0x5e, 0xc0, 0xc2, 0x3a, 0xea, 0x52, 0x16, 0x1a, 0xc9, 0x1a, 0x5c, 0x85, 0xba, 0x71, 0x33, 0x92,
-	0xee, 0x61, 0x71, 0xe0, 0x45, 0xc7, 0x5c, 0x82, 0xc3, 0xec, 0xe7, 0x49, 0xe4, 0x30, 0xf3, 0x8a,
-	0x2d, 0x62, 0x12, 0x39, 0x31, 0xd9, 0x00, 0x98, 0xad, 0x99, 0x40, 0xb5, 0x4f, 0x1b, 0x4a, 0xaf,
-	0x42, 0xb7, 0xb0, 0xac, 0xad, 0xbe, 0xc3, 0xea, 0x79, 0x0b, 0x7b, 0x84, 0x55, 0x84, 0x19, 0xd7,
-	0x15, 0xaa, 0x16, 0xf8, 0x1f, 0x46, 0x3c, 0x6d, 0x16, 0x1a, 0xf1, 0xd4, 0x1c, 0xc1, 0xd2, 0x54,
-	0xb5, 0x87, 0x19, 0x4d, 0x2f, 0x61, 0x7d, 0x1a, 0xfb, 0xed, 0x92, 0x9d, 0x80, 0x79, 0xd8, 0xe6,
-	0x4d, 0x5e, 0xc1, 0x0d, 0x65, 0x46, 0xae, 0x83, 0xbf, 0x13, 0x0f, 0x4e, 0x71, 0xfb, 0x37, 0x83,
-	0x7a, 0x6b, 0x3c, 0xfd, 0xb7, 0x13, 0x30, 0x35, 0xd7, 0xa2, 0x22, 0x09, 0x8c, 0x8d, 0x22, 0x67,
-	0x0d, 0x7a, 0x41, 0xfb, 0xb7, 0xc3, 0x9a, 0x3b, 0xdc, 0x27, 0xcc, 0xda, 0x0c, 0x88, 0xec, 0xe9,
-	0xbb, 0x73, 0x3e, 0x3f, 0x02, 0xf7, 0xef, 0x87, 0x0f, 0xb4, 0xf0, 0xa7, 0xc9, 0x8b, 0xcb, 0x4a,
-	0x1e, 0x4f, 0xed, 0x5f, 0xfc, 0xf0, 0x15, 0x00, 0x00, 0xff, 0xff, 0x0d, 0x74, 0x0d, 0x5e, 0xd8,
-	0x02, 0x00, 0x00,
+func init() { proto.RegisterFile("logScribe.proto", fileDescriptor_logScribe_2d3ca41b3d7f0dfa) }
+
+var fileDescriptor_logScribe_2d3ca41b3d7f0dfa = []byte{
+	// 933 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x4f, 0x6f, 0xe3, 0x44,
+	0x14, 0x8f, 0xed, 0xfc, 0x7d, 0x29, 0xad, 0x35, 0xaa, 0x90, 0x15, 0x21, 0xa8, 0xbc, 0x07, 0xa2,
+	0xb2, 0x84, 0x2a, 0x68, 0x11, 0x20, 0x71, 0x48, 0x1b, 0x67, 0x15, 0x11, 0xd2, 0xd5, 0x38, 0x65,
+	0x05, 0x97, 0xae, 0xeb, 0x4c, 0x9c, 0x51, 0x63, 0x4f, 0x76, 0x3c, 0x59, 0xb5, 0xe2, 0xc6, 0x05,
+	0x89, 0x0b, 0x37, 0xbe, 0x01, 0x1f, 0x83, 0x2b, 0x9f, 0x0b, 0xcd, 0x8c, 0x9d, 0xb8, 0x02, 0x36,
+	0xee, 0x8a, 0xdb, 0x7b, 0x4f, 0xef, 0xcf, 0xef, 0xfd, 0xde, 0x9b, 0x67, 0xc3, 0xd1, 0x8a, 0x45,
+	0x7e, 0xc8, 0xe9, 0x0d, 0xe9, 0xad, 0x39, 0x13, 0x0c, 0x7d, 0x18, 0xb2, 0xb8, 0xc7, 0x59, 0x1c,
+	0x24, 0x2c, 0x15, 0x9c, 0x84, 0xcb, 0x15, 0x4d, 0x7b, 0xa9, 0xf6, 0x08, 0xd6, 0xd4, 0xfd, 0xd5,
+	0x02, 0x98, 0xb0, 0x08, 0x93, 0xd7, 0x1b, 0x92, 0x0a, 0xd4, 0x81, 0xe6, 0x82, 0xae, 0x48, 0x12,
+	0xc4, 0xc4, 0x31, 0x4e, 0x8c, 0x6e, 0x0b, 0x6f, 0x75, 0x84, 0xa0, 0xba, 0x0e, 0xc4, 0xd2, 0x31,
+	0x95, 0x5d, 0xc9, 0xd2, 0xb6, 0xa2, 0x09, 0x71, 0x2c, 0x6d, 0x93, 0x32, 0x1a, 0x42, 0x33, 0x25,
+	0x6f, 0x08, 0xa7, 0xe2, 0xde, 0xa9, 0x9e, 0x18, 0xdd, 0xc3, 0x7e, 0xb7, 0xf7, 0x76, 0x14, 0x3d,
+	0x3f, 0xf3, 0xc7, 0xdb, 0x48, 0xf4, 0x01, 0xb4, 0x04, 0x8d, 0x49, 0x2a, 0x82, 0x78, 0xed, 0xd4,
+	0x4e, 0x8c, 0xae, 0x85, 0x77, 0x06, 0x59, 0x77, 0xc9, 0x52, 0xe1, 0xd4, 0x75, 0x5d, 0x29, 0xa3,
+	0x29, 0xd4, 0x17, 0x94, 0xac, 0xe6, 0xa9, 0xd3, 0x38, 0xb1, 0xba, 0xed, 0xfe, 0x17, 0xfb, 0xaa,
+	0xee, 0xfa, 0xee, 0x8d, 0x54, 0xa0, 0x97, 0x08, 0x7e, 0x8f, 0xb3, 0x2c, 0xe8, 0x19, 0x58, 0x41,
+	0x78, 0xeb, 0x34, 0x55, 0x0b, 0x4f, 0xf6, 0x25, 0x1b, 0x84, 0xb7, 0x58, 0xfa, 0x77, 0xbe, 0x82,
+	0x76, 0x21, 0x1b, 0xb2, 0xc1, 0xba, 0x25, 0xf7, 0x19, 0x99, 0x52, 0x44, 0xc7, 0x50, 0x7b, 0x13,
+	0xac, 0x36, 0x24, 0x23, 0x52, 0x2b, 0x5f, 0x9b, 0x5f, 0x1a, 0xee, 0x13, 0x68, 0x2b, 0x4c, 0xe9,
+	0x9a, 0x25, 0x29, 0x41, 0xc7, 0x60, 0x71, 0x92, 0xea, 0xd0, 0x73, 0xd3, 0x31, 0xb0, 0x54, 0xdd,
+	0x2e, 0xd8, 0x13, 0x16, 0xf9, 0x82, 0x93, 0x20, 0xf6, 0x37, 0x71, 0x1c, 0x70, 0x95, 0x32, 0x64,
+	0x9b, 0x44, 0x28, 0x5f, 0x0b, 0x6b, 0xc5, 0xfd, 0xc5, 0x80, 0xe6, 0x84, 0x45, 0xe7, 0x81, 0x08,
+	0x97, 0x68, 0x08, 0x0d, 0x92, 0x08, 0x4e, 0x55, 0x42, 0x49, 0xcf, 0x69, 0x79, 0x7a, 0x70, 0x1e,
+	0x9a, 0x73, 0x62, 0x3e, 0x8e, 0x93, 0x0c, 0xb3, 0x02, 0x52, 0xe8, 0xee, 0xdf, 0x30, 0xfb, 0xd0,
+	0x9e, 0x05, 0x74, 0xf5, 0xae, 0xfb, 0x78, 0x0c, 0x35, 0xb9, 0x83, 0xa9, 0x5a, 0xc8, 0x1a, 0xd6,
+	0x8a, 0xfb, 0x1d, 0x34, 0x26, 0x2c, 0x9a, 0xc8, 0xe5, 0xfc, 0x1f, 0x16, 0xdc, 0xfd, 0xcd, 0x80,
+	0x36, 0x26, 0xc1, 0xfc, 0x5d, 0x41, 0xbe, 0x0f, 0x75, 0xb6, 0x58, 0xa4, 0x44, 0xa8, 0xac, 0x16,
+	0xce, 0x34, 0x0d, 0x3e, 0xa6, 0xc2, 0xa9, 0xe6, 0xe0, 0x63, 0x2a, 0x64, 0x86, 0x05, 0x67, 0x71,
+	0xf6, 0x06, 0x94, 0x8c, 0x0e, 0xc1, 0x14, 0x4c, 0x2d, 0xbf, 0x85, 0x4d, 0xc1, 0xdc, 0x9f, 0x0d,
+	0x38, 0xd0, 0x88, 0x32, 0x72, 0xbf, 0xc9, 0x79, 0xd0, 0xb3, 0xfe, 0xb8, 0xc4, 0xac, 0x25, 0x3d,
+	0x19, 0x61, 0xe8, 0x23, 0x68, 0x27, 0xe4, 0x4e, 0x5c, 0x67, 0x30, 0x4d, 0x55, 0x08, 0xa4, 0xe9,
+	0x52, 0x43, 0x45, 0x50, 0x8d, 0x19, 0xd7, 0xb4, 0x34, 0xb1, 0x92, 0xdd, 0xdf, 0x0d, 0x78, 0xcf,
+	0x27, 0x01, 0x0f, 0x97, 0x39, 0x31, 0xc7, 0x50, 0x7b, 0xbd, 0x21, 0x3c, 0xdf, 0x7e, 0xad, 0x48,
+	0x2b, 0x27, 0x11, 0xb9, 0x53, 0x69, 0x9b, 0x58, 0x2b, 0xb2, 0xa4, 0x24, 0xe7, 0x7a, 0xcd, 0xc9,
+	0x82, 0xde, 0x65, 0x7c, 0x83, 0x34, 0xbd, 0x50, 0x96, 0x2d, 0x0f, 0xd5, 0x7f, 0xf0, 0x50, 0xcb,
+	0x79, 0xd8, 0x31, 0x58, 0x2f, 0x30, 0xe8, 0xfe, 0x61, 0xc0, 0x41, 0x0e, 0x2c, 0xdd, 0xac, 0x84,
+	0x1c, 0x80, 0xee, 0x3d, 0x03, 0x96, 0x69, 0x0f, 0x06, 0x69, 0xfe, 0xc7, 0x20, 0xad, 0xc2, 0x20,
+	0x1d, 0x68, 0xa4, 0x24, 0x8a, 0x49, 0xa2, 0x47, 0xd6, 0xc2, 0xb9, 0x2a, 0xbb, 0x91, 0x4c, 0x5e,
+	0x27, 0x9b, 0xf8, 0x86, 0xf0, 0x0c, 0x21, 0x48, 0xd3, 0x54, 0x59, 0xb6, 0x7b, 0x55, 0x2f, 0xec,
+	0xd5, 0x33, 0x38, 0xc2, 0x24, 0xa2, 0xa9, 0x20, 0x3c, 0x67, 0xf0, 0x10, 0x4c, 0x3a, 0xcf, 0x50,
+	0x9a, 0x74, 0x2e, 0xc3, 0x82, 0xf9, 0x9c, 0xe7, 0xeb, 0x24, 0x65, 0xf9, 0xb8, 0x76, 0x61, 0x6f,
+	0x3b, 0x1d, 0xa7, 0x63, 0xb0, 0x06, 0xe1, 0x2d, 0x3a, 0x82, 0xf6, 0xe0, 0xe2, 0xdb, 0xeb, 0xa1,
+	0x37, 0x1a, 0x5c, 0x4d, 0x66, 0x76, 0x05, 0xd9, 0x70, 0x20, 0x0d, 0xd8, 0xbb, 0xf0, 0xc6, 0xdf,
+	0x7b, 0x43, 0xdb, 0xc8, 0x5d, 0x5e, 0xe2, 0xf1, 0x6c, 0xe6, 0x4d, 0x6d, 0x13, 0x1d, 0x02, 0x48,
+	0x83, 0xff, 0xc3, 0xf4, 0xc2, 0x1b, 0xda, 0xd6, 0xa9, 0x0f, 0xcd, 0xfc, 0x68, 0x4b, 0xe7, 0xab,
+	0xa9, 0xff, 0xc2, 0xbb, 0x18, 0x8f, 0xc6, 0xde, 0xd0, 0xae, 0xa0, 0x16, 0xd4, 0x86, 0xde, 0xf9,
+	0xd5, 0x73, 0xdb, 0x40, 0x4d, 0xa8, 0x8e, 0xa7, 0xa3, 0x4b, 0xdb, 0x44, 0x6d, 0x68, 0xbc, 0x1c,
+	0xe0, 0xe9, 0x78, 0xfa, 0xdc, 0xb6, 0xa4, 0x87, 0x87, 0xf1, 0x25, 0xb6, 0xab, 0x52, 0x1c, 0x0d,
+	0x66, 0x83, 0x89, 0x5d, 0xeb, 0xff, 0x65, 0x42, 0x6b, 0x92, 0x7f, 0xc0, 0xd0, 0x2b, 0xb0, 0x26,
+	0x2c, 0x42, 0x8f, 0xb8, 0x53, 0x9d, 0x4f, 0x4a, 0xf9, 0x6a, 0x8e, 0xdc, 0x0a, 0x8a, 0xa1, 0xb5,
+	0x3d, 0xa5, 0x8f, 0xaa, 0x73, 0x56, 0xc2, 0xf7, 0xc1, 0x85, 0x76, 0x2b, 0x5d, 0x03, 0x2d, 0x0b,
+	0xe7, 0xb8, 0x5b, 0x22, 0x83, 0xf2, 0xec, 0x9c, 0x95, 0xf5, 0xdc, 0x35, 0xd6, 0xff, 0x53, 0x13,
+	0x29, 0x4f, 0x02, 0xe1, 0xe8, 0x15, 0x54, 0xe5, 0x4d, 0x45, 0x7b, 0xd9, 0x29, 0x5c, 0xde, 0x4e,
+	0xd9, 0x93, 0xe1, 0x56, 0xce, 0x0c, 0x14, 0x42, 0x55, 0xd6, 0xda, 0x5f, 0xa1, 0x70, 0x36, 0x3b,
+	0x4f, 0xcb, 0x39, 0x6f, 0xa7, 0x45, 0xa1, 0xae, 0x5f, 0x31, 0xfa, 0x74, 0xff, 0xff, 0x44, 0xe1,
+	0x0c, 0x75, 0x9e, 0x96, 0x75, 0x97, 0xc7, 0x41, 0xf6, 0xd3, 0xff, 0x09, 0x9a, 0xf9, 0x93, 0x42,
+	0xac, 0x20, 0x7f, 0xb6, 0x1f, 0xf2, 0x83, 0xf7, 0xdb, 0x39, 0x2b, 0x1f, 0x90, 0xf7, 0x79, 0x5e,
+	0xfb, 0xd1, 0x0a, 0xd6, 0xf4, 0xa6, 0xae, 0x7e, 0xe0, 0x3e, 0xff, 0x7b, 0x00, 0x04, 0x25, 0x00,
+	0x52, 0xd3, 0x09, 0x00, 0x00,
 }
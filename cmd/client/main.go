@@ -15,6 +15,7 @@ import (
 	pb "github.com/RomanosTrechlis/go-scribe/api"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	_ "google.golang.org/grpc/encoding/gzip"
 )
 
 const (
@@ -27,6 +28,8 @@ func main() {
 	var scribe string
 	var filename string
 	sec := flag.Bool("s", false, "true for secure connection")
+	streaming := flag.Bool("stream", false, "true for sending requests through a single stream")
+	gzip := flag.Bool("gzip", false, "true for compressing requests with gzip")
 	flag.StringVar(&scribe, "addr", ":8080", "scribe's address")
 	flag.StringVar(&filename, "filename", "test", "filename to write the logs")
 	flag.Parse()
@@ -38,6 +41,11 @@ func main() {
 		server = "127.0.0.1"
 	}
 
+	var opts []grpc.DialOption
+	if *gzip {
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.UseCompressor("gzip")))
+	}
+
 	var conn *grpc.ClientConn
 	if *sec {
 		// Load the client certificates from disk
@@ -68,24 +76,29 @@ func main() {
 		})
 
 		// Create a connection with the TLS credentials
-		conn, err = grpc.Dial(server+port,
+		conn, err = grpc.Dial(server+port, append(opts,
 			grpc.WithTransportCredentials(creds),
-			grpc.WithTimeout(1*time.Second))
+			grpc.WithTimeout(1*time.Second))...)
 		if err != nil {
 			log.Fatalf("did not connect: %v", err)
 		}
 		defer conn.Close()
 	} else {
 		var err error
-		conn, err = grpc.Dial(server+port,
+		conn, err = grpc.Dial(server+port, append(opts,
 			grpc.WithInsecure(),
-			grpc.WithTimeout(1*time.Second))
+			grpc.WithTimeout(1*time.Second))...)
 		if err != nil {
 			log.Fatalf("did not connect: %v", err)
 		}
 		defer conn.Close()
 	}
 
+	if *streaming {
+		stream(conn, filename)
+		return
+	}
+
 	var i int
 	for {
 		i++
@@ -103,3 +116,26 @@ func main() {
 	}
 
 }
+
+func stream(conn *grpc.ClientConn, filename string) {
+	c := pb.NewLogScribeClient(conn)
+	s, err := c.LogStream(context.Background())
+	if err != nil {
+		log.Fatalf("failled to open stream: %v", err)
+	}
+
+	var i int
+	for {
+		i++
+		req := &pb.LogRequest{
+			Filename: filename,
+			Path:     "path",
+			Line:     fmt.Sprintf("%d: This is a test", i),
+		}
+		err := s.Send(req)
+		if err != nil {
+			_, err = s.CloseAndRecv()
+			log.Fatalf("failled: %v", err)
+		}
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"github.com/rs/xid"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -66,6 +68,46 @@ func fillAgentConfigFromFlags(flags map[string]string) (*types.AgentConfig, erro
 		return nil, fmt.Errorf("failed to get the value of 'size' flag: %v", err)
 	}
 	path := c.StringValue("path", "agent", flags)
+	rotate := c.StringValue("rotate", "agent", flags)
+	tz := c.StringValue("tz", "agent", flags)
+	compress := c.StringValue("compress", "agent", flags)
+	maxAge, err := time.ParseDuration(c.StringValue("maxage", "agent", flags))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the value of 'maxage' flag: %v", err)
+	}
+	maxSegs, err := c.IntValue("maxsegs", "agent", flags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the value of 'maxsegs' flag: %v", err)
+	}
+	var budget int64
+	if b := c.StringValue("budget", "agent", flags); b != "" {
+		budget, err = scribe.LexicalToNumber(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the value of 'budget' flag: %v", err)
+		}
+	}
+	retInterval, err := time.ParseDuration(c.StringValue("retint", "agent", flags))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the value of 'retint' flag: %v", err)
+	}
+	dryRun, err := c.BoolValue("dryrun", "agent", flags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the value of 'dryrun' flag: %v", err)
+	}
+	var low, high int64
+	if l := c.StringValue("low", "agent", flags); l != "" {
+		low, err = scribe.LexicalToNumber(l)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the value of 'low' flag: %v", err)
+		}
+	}
+	if h := c.StringValue("high", "agent", flags); h != "" {
+		high, err = scribe.LexicalToNumber(h)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the value of 'high' flag: %v", err)
+		}
+	}
+	keep := c.StringValue("keep", "agent", flags)
 	verbose, err := c.BoolValue("verbose", "agent", flags)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get the value of 'verbose' flag: %v", err)
@@ -75,12 +117,104 @@ func fillAgentConfigFromFlags(flags map[string]string) (*types.AgentConfig, erro
 		return nil, fmt.Errorf("failed to get the value of 'console' flag: %v", err)
 	}
 
+	hport, err := c.IntValue("hport", "agent", flags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the value of 'hport' flag: %v", err)
+	}
+
+	sudp := c.StringValue("sudp", "agent", flags)
+	stcp := c.StringValue("stcp", "agent", flags)
+	srule := c.StringValue("srule", "agent", flags)
+	rtcp := c.StringValue("rtcp", "agent", flags)
+	runix := c.StringValue("runix", "agent", flags)
+	compression := c.StringValue("compression", "agent", flags)
+
+	format := c.StringValue("format", "agent", flags)
+	layout := c.StringValue("layout", "agent", flags)
+	ack := c.StringValue("ack", "agent", flags)
+	flush := c.StringValue("flush", "agent", flags)
+	buffer, err := scribe.LexicalToNumber(c.StringValue("buffer", "agent", flags))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the value of 'buffer' flag: %v", err)
+	}
+	flushInterval, err := time.ParseDuration(c.StringValue("flushint", "agent", flags))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the value of 'flushint' flag: %v", err)
+	}
+	sync := c.StringValue("sync", "agent", flags)
+	syncInterval, err := time.ParseDuration(c.StringValue("syncint", "agent", flags))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the value of 'syncint' flag: %v", err)
+	}
+	spool := c.StringValue("spool", "agent", flags)
+	workers, err := c.IntValue("workers", "agent", flags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the value of 'workers' flag: %v", err)
+	}
+	queue, err := c.IntValue("queue", "agent", flags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the value of 'queue' flag: %v", err)
+	}
+	onError := c.StringValue("onerror", "agent", flags)
+	retries, err := c.IntValue("retries", "agent", flags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the value of 'retries' flag: %v", err)
+	}
+	backoff, err := time.ParseDuration(c.StringValue("backoff", "agent", flags))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the value of 'backoff' flag: %v", err)
+	}
+
 	crt := c.StringValue("crt", "agent", flags)
 	pk := c.StringValue("pk", "agent", flags)
 	ca := c.StringValue("ca", "agent", flags)
-	a := &types.AgentConfig{port, pprofInfo, console, verbose,
-	mediator, pport, path, maxSize,
-	types.CertificateConfig{crt, pk, ca}}
+	a := &types.AgentConfig{
+		Port:               port,
+		Profile:            pprofInfo,
+		Console:            console,
+		Verbose:            verbose,
+		Mediator:           mediator,
+		ProfilePort:        pport,
+		LogPath:            path,
+		LogFileSize:        maxSize,
+		Rotation:           rotate,
+		Timezone:           tz,
+		SegmentCompression: compress,
+		RetentionAge:       maxAge,
+		RetentionSegments:  maxSegs,
+		RetentionBytes:     budget,
+		RetentionInterval:  retInterval,
+		RetentionDryRun:    dryRun,
+		DiskLow:            low,
+		DiskHigh:           high,
+		DiskKeep:           keep,
+		LogFormat:          format,
+		LogLayout:          layout,
+		AckMode:            ack,
+		FlushPolicy:        flush,
+		BufferSize:         buffer,
+		FlushInterval:      flushInterval,
+		SyncPolicy:         sync,
+		SyncInterval:       syncInterval,
+		SpoolPath:          spool,
+		Workers:            workers,
+		QueueDepth:         queue,
+		OnError:            onError,
+		Retries:            retries,
+		Backoff:            backoff,
+		HTTPPort:           hport,
+		SyslogUDP:          sudp,
+		SyslogTCP:          stcp,
+		SyslogRule:         srule,
+		RawTCP:             rtcp,
+		RawUnix:            runix,
+		Compression:        compression,
+		CertificateConfig: types.CertificateConfig{
+			Certificate:          crt,
+			PrivateKey:           pk,
+			CertificateAuthority: ca,
+		},
+	}
 	return a, nil
 }
 
@@ -124,8 +258,31 @@ func agentHandler(flags map[string]string) error {
 		os.Exit(2)
 	}
 
+	keep, err := scribe.ParseKeep(conf.DiskKeep)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "disk keep is not valid: %v\n", err)
+		os.Exit(2)
+	}
+
 	s, err := scribe.New(id, conf.LogPath, conf.Port, conf.LogFileSize, conf.Mediator,
-		conf.Certificate, conf.PrivateKey, conf.CertificateAuthority)
+		conf.Certificate, conf.PrivateKey, conf.CertificateAuthority,
+		scribe.WithRotation(conf.Rotation, conf.Timezone),
+		scribe.WithSegmentCompression(conf.SegmentCompression),
+		scribe.WithRetention(scribe.RetentionPolicy{
+			MaxAge:      conf.RetentionAge,
+			MaxSegments: conf.RetentionSegments,
+			MaxBytes:    conf.RetentionBytes,
+			Interval:    conf.RetentionInterval,
+			DryRun:      conf.RetentionDryRun,
+		}),
+		scribe.WithWatermarks(scribe.Watermarks{Low: conf.DiskLow, High: conf.DiskHigh, Keep: keep}),
+		scribe.WithFormat(conf.LogFormat, conf.LogLayout),
+		scribe.WithAck(conf.AckMode),
+		scribe.WithFlush(conf.FlushPolicy, conf.BufferSize, conf.FlushInterval),
+		scribe.WithSync(conf.SyncPolicy, conf.SyncInterval),
+		scribe.WithSpool(conf.SpoolPath),
+		scribe.WithWorkers(conf.Workers, conf.QueueDepth),
+		scribe.WithErrorPolicy(scribe.ErrorPolicy{OnError: conf.OnError, Retries: conf.Retries, Backoff: conf.Backoff}))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to create scribe: %v", err)
 		os.Exit(2)
@@ -140,6 +297,30 @@ func agentHandler(flags map[string]string) error {
 	defer s.Shutdown()
 	go s.Serve()
 
+	if conf.HTTPPort != 0 {
+		hsrv, err := serveHTTP(conf.HTTPPort, s.Logger(), conf.CertificateConfig)
+		if err != nil {
+			return err
+		}
+		defer hsrv.Shutdown(context.Background())
+	}
+
+	if conf.SyslogUDP != "" || conf.SyslogTCP != "" {
+		sl, err := serveSyslog(conf, s.Logger())
+		if err != nil {
+			return err
+		}
+		defer sl.Close()
+	}
+
+	if conf.RawTCP != "" || conf.RawUnix != "" {
+		rs, err := serveRaw(conf, s.Logger())
+		if err != nil {
+			return err
+		}
+		defer rs.Close()
+	}
+
 	var srv *http.Server
 	if conf.Profile {
 		srv = profiling.Serve(conf.ProfilePort)
@@ -159,9 +340,13 @@ func agentHandler(flags map[string]string) error {
 }
 
 func addMediator(id string, conf *types.AgentConfig) error {
-	conn, err := grpc.Dial(conf.Mediator,
+	opts, err := gserver.DialCompression(conf.Compression)
+	if err != nil {
+		return err
+	}
+	conn, err := grpc.Dial(conf.Mediator, append(opts,
 		grpc.WithInsecure(),
-		grpc.WithTimeout(1*time.Second))
+		grpc.WithTimeout(1*time.Second))...)
 	if err != nil {
 		log.Fatalf("did not connect: %v", err)
 	}
@@ -179,21 +364,19 @@ func addMediator(id string, conf *types.AgentConfig) error {
 	var retries = 3
 	var success = false
 	for retries > 0 {
-		r, err := cl.Register(context.Background(), req)
+		_, err := cl.Register(context.Background(), req)
+		if status.Code(err) == codes.InvalidArgument {
+			// retrying the same request is pointless
+			return fmt.Errorf("mediator '%s' rejected registration: %s",
+				conf.Mediator, status.Convert(err).Message())
+		}
 		if err != nil {
 			retries--
-			p.Print(fmt.Sprintf("Failed to register to mediator '%s. "+
-				"Remaining tries: %d", conf.Mediator, retries))
+			p.Print(fmt.Sprintf("Failed to register to mediator '%s': %s. "+
+				"Remaining tries: %d", conf.Mediator, status.Convert(err).Message(), retries))
 			time.Sleep(1 * time.Second)
 			continue
 		}
-		if r.GetRes() != "Success" {
-			retries--
-			p.Print(fmt.Sprintf("Failed to register to mediator '%s'. "+
-				"Remaining tries: %d", conf.Mediator, retries))
-			time.Sleep(2 * time.Second)
-			continue
-		}
 		success = true
 		break
 	}
@@ -210,6 +393,23 @@ func infoBlock(conf *types.AgentConfig) {
 	fmt.Println("\t==>\tPort number:\t", conf.Port)
 	fmt.Println("\t==>\tLog path:\t", conf.LogPath)
 	fmt.Println("\t==>\tLog size:\t", conf.LogFileSize)
+	fmt.Println("\t==>\tRotation:\t", conf.Rotation, conf.Timezone)
+	fmt.Println("\t==>\tSegment compression:\t", conf.SegmentCompression)
+	fmt.Println("\t==>\tRetention:\t", conf.RetentionAge, conf.RetentionSegments, conf.RetentionBytes)
+	fmt.Println("\t==>\tWatermarks:\t", conf.DiskLow, conf.DiskHigh, conf.DiskKeep)
+	fmt.Println("\t==>\tLog format:\t", conf.LogFormat)
+	fmt.Println("\t==>\tAck mode:\t", conf.AckMode)
+	fmt.Println("\t==>\tFlush policy:\t", conf.FlushPolicy)
+	fmt.Println("\t==>\tSync policy:\t", conf.SyncPolicy)
+	fmt.Println("\t==>\tSpool path:\t", conf.SpoolPath)
+	fmt.Println("\t==>\tWorkers:\t", conf.Workers, conf.QueueDepth)
+	fmt.Println("\t==>\tOn error:\t", conf.OnError, conf.Retries, conf.Backoff)
+	fmt.Println("\t==>\tHTTP port:\t", conf.HTTPPort)
+	fmt.Println("\t==>\tSyslog UDP:\t", conf.SyslogUDP)
+	fmt.Println("\t==>\tSyslog TCP:\t", conf.SyslogTCP)
+	fmt.Println("\t==>\tRaw TCP:\t", conf.RawTCP)
+	fmt.Println("\t==>\tRaw socket:\t", conf.RawUnix)
+	fmt.Println("\t==>\tMediator compression:\t", conf.Compression)
 	fmt.Println("\t==>\tPprof server:\t", conf.Profile)
 	fmt.Println("\t==>\tPprof port:\t", conf.ProfilePort)
 	fmt.Println("##########################################################")
@@ -2,8 +2,8 @@ package main
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -11,7 +11,10 @@ import (
 	p "github.com/RomanosTrechlis/go-scribe/internal/util/format/print"
 	"github.com/RomanosTrechlis/go-scribe/mediator"
 	"github.com/RomanosTrechlis/go-scribe/scribe"
+	"github.com/RomanosTrechlis/go-scribe/service"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type cliScribe struct {
@@ -53,15 +56,24 @@ func (cl cliScribe) GetVersion(ctx context.Context, in *pb.VersionRequest) (*pb.
 func (cl cliScribe) GetStats(ctx context.Context, in *pb.StatsRequest) (*pb.StatsResponse, error) {
 	if !cl.isMediator {
 		info := cl.scribe.GetInfo()
+		disk := cl.scribe.Disk()
+		errs := cl.scribe.Errors()
 		for k, v := range info.ScribesCounter {
 			return &pb.StatsResponse{
 				Result: []*pb.StatsResponse_Result{{
-					Name:  k,
-					Count: v,
+					Name:         k,
+					Count:        v,
+					Degraded:     disk.Degraded,
+					FreeBytes:    disk.Free,
+					Shed:         disk.Shed,
+					Failed:       errs.Failed,
+					Retried:      errs.Retried,
+					Skipped:      errs.Skipped,
+					DeadLettered: errs.DeadLettered,
 				}},
 			}, nil
 		}
-		return nil, errors.New("couldn't get stats for scribe")
+		return nil, status.Error(codes.Internal, "couldn't get stats for scribe")
 	}
 
 	response := &pb.StatsResponse{
@@ -73,7 +85,7 @@ func (cl cliScribe) GetStats(ctx context.Context, in *pb.StatsRequest) (*pb.Stat
 
 func (cl cliScribe) GetScribesResponsibility(ctx context.Context, in *pb.ResponsibilityRequest) (*pb.ResponsibilityResponse, error) {
 	if !cl.isMediator {
-		return nil, errors.New("rpc works for mediators only")
+		return nil, status.Error(codes.Unimplemented, "rpc works for mediators only")
 	}
 
 	response := &pb.ResponsibilityResponse{
@@ -90,6 +102,73 @@ func (cl cliScribe) GetScribesResponsibility(ctx context.Context, in *pb.Respons
 	return response, nil
 }
 
+func (cl cliScribe) ListFiles(ctx context.Context, in *pb.ListFilesRequest) (*pb.ListFilesResponse, error) {
+	if !cl.isMediator {
+		files, err := cl.scribe.ListFiles(in.GetPathPrefix())
+		if err != nil {
+			return nil, service.Status(err)
+		}
+		return &pb.ListFilesResponse{Files: files}, nil
+	}
+
+	response := &pb.ListFilesResponse{
+		Files: make([]*pb.ListFilesResponse_File, 0),
+	}
+	info := cl.mediator.GetInfo()
+	for k, v := range info.Scribes {
+		fr, err := getFilesFor(v, in)
+		if err != nil {
+			p.Print(fmt.Sprintf("failed to list files for %s: %v\n", k, err))
+			continue
+		}
+		for _, f := range fr.GetFiles() {
+			f.Scribe = k
+		}
+		response.Files = append(response.Files, fr.GetFiles()...)
+	}
+	sort.Slice(response.Files, func(i, j int) bool {
+		a, b := response.Files[i], response.Files[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Scribe < b.Scribe
+	})
+	return response, nil
+}
+
+func (cl cliScribe) GetRetention(ctx context.Context, in *pb.RetentionRequest) (*pb.RetentionResponse, error) {
+	if !cl.isMediator {
+		deletions, err := cl.scribe.Retention(in.GetRun())
+		if err != nil {
+			return nil, service.Status(err)
+		}
+		return &pb.RetentionResponse{Deletions: deletions}, nil
+	}
+
+	response := &pb.RetentionResponse{
+		Deletions: make([]*pb.RetentionResponse_Deletion, 0),
+	}
+	info := cl.mediator.GetInfo()
+	for k, v := range info.Scribes {
+		rr, err := getRetentionFor(v, in)
+		if err != nil {
+			p.Print(fmt.Sprintf("failed to get retention for %s: %v\n", k, err))
+			continue
+		}
+		for _, d := range rr.GetDeletions() {
+			d.Scribe = k
+		}
+		response.Deletions = append(response.Deletions, rr.GetDeletions()...)
+	}
+	sort.SliceStable(response.Deletions, func(i, j int) bool {
+		return response.Deletions[i].Time < response.Deletions[j].Time
+	})
+	return response, nil
+}
+
 func (cl cliScribe) getStatsForScribes(resp *pb.StatsResponse) *pb.StatsResponse {
 	info := cl.mediator.GetInfo()
 	for k, v := range info.Scribes {
@@ -99,8 +178,15 @@ func (cl cliScribe) getStatsForScribes(resp *pb.StatsResponse) *pb.StatsResponse
 			continue
 		}
 		result := &pb.StatsResponse_Result{
-			Name:  k,
-			Count: vr.Result[0].Count,
+			Name:         k,
+			Count:        vr.Result[0].Count,
+			Degraded:     vr.Result[0].Degraded,
+			FreeBytes:    vr.Result[0].FreeBytes,
+			Shed:         vr.Result[0].Shed,
+			Failed:       vr.Result[0].Failed,
+			Retried:      vr.Result[0].Retried,
+			Skipped:      vr.Result[0].Skipped,
+			DeadLettered: vr.Result[0].DeadLettered,
 		}
 		resp.Result = append(resp.Result, result)
 	}
@@ -157,6 +243,38 @@ func getStatsFor(host string) (*pb.StatsResponse, error) {
 	return client.GetStats(context.Background(), &pb.StatsRequest{})
 }
 
+func getFilesFor(host string, in *pb.ListFilesRequest) (*pb.ListFilesResponse, error) {
+	if strings.Contains(host, ":") {
+		host = strings.Split(host, ":")[0]
+	}
+	conn, err := grpc.Dial(host+":4242",
+		grpc.WithInsecure(),
+		grpc.WithTimeout(1*time.Second))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	client := pb.NewCLIScribeClient(conn)
+	return client.ListFiles(context.Background(), in)
+}
+
+func getRetentionFor(host string, in *pb.RetentionRequest) (*pb.RetentionResponse, error) {
+	if strings.Contains(host, ":") {
+		host = strings.Split(host, ":")[0]
+	}
+	conn, err := grpc.Dial(host+":4242",
+		grpc.WithInsecure(),
+		grpc.WithTimeout(1*time.Second))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	client := pb.NewCLIScribeClient(conn)
+	return client.GetRetention(context.Background(), in)
+}
+
 func registerCLIScribeFunc(srv *grpc.Server, c cliScribe) func() {
 	return func() {
 		pb.RegisterCLIScribeServer(srv, c)
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	p "github.com/RomanosTrechlis/go-scribe/internal/util/format/print"
+	"github.com/RomanosTrechlis/go-scribe/internal/util/gserver"
+	"github.com/RomanosTrechlis/go-scribe/service"
+	"github.com/RomanosTrechlis/go-scribe/types"
+)
+
+// serveHTTP exposes the logger on the HTTP endpoint, using
+// the same certificates as the gRPC server for HTTPS.
+func serveHTTP(port int, l service.Logger, cert types.CertificateConfig) (*http.Server, error) {
+	mux := http.NewServeMux()
+	mux.Handle(service.LogPath, l)
+	srv, err := gserver.ServeHTTP(fmt.Sprintf(":%d", port), mux,
+		cert.Certificate, cert.PrivateKey, cert.CertificateAuthority)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start http endpoint: %v", err)
+	}
+	p.Print(fmt.Sprintf("HTTP endpoint listens on :%d%s", port, service.LogPath))
+	return srv, nil
+}
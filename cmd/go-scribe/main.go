@@ -4,6 +4,8 @@ import (
 	"os"
 
 	"github.com/RomanosTrechlis/go-icls/cli"
+	"github.com/RomanosTrechlis/go-scribe/scribe"
+	"github.com/RomanosTrechlis/go-scribe/service"
 )
 
 var version = "undefined"
@@ -22,6 +24,38 @@ func init() {
 	agent.IntFlag("pport", "", 1111, "port for pprof server", false)
 	agent.StringFlag("path", "", "../../logs", "path for logs to be persisted", false)
 	agent.StringFlag("size", "", "1MB", "max size for individual files, -1B for infinite size", false)
+	agent.StringFlag("rotate", "", scribe.RotateNone, "time based rotation of files: none, hourly, daily or a duration, i.e. 6h", false)
+	agent.StringFlag("tz", "", "", "timezone of the rotation times, i.e. UTC, the local one by default", false)
+	agent.StringFlag("compress", "", scribe.CompressNone, "compression of rotated files: gzip or none", false)
+	agent.StringFlag("maxage", "", "0s", "deletes rotated files older than this, i.e. 720h, 0s keeps them", false)
+	agent.IntFlag("maxsegs", "", 0, "number of rotated files kept for every file, 0 keeps them all", false)
+	agent.StringFlag("budget", "", "", "disk budget of every path, deleting its oldest rotated files, i.e. 10GB", false)
+	agent.StringFlag("retint", "", "1m", "time between deletions of rotated files", false)
+	agent.BoolFlag("dryrun", "", "reports the rotated files to delete without deleting them", false)
+	agent.StringFlag("low", "", "", "free space of the log path below which requests get rejected, i.e. 1GB", false)
+	agent.StringFlag("high", "", "", "free space of the log path at which every request is accepted again, -low by default", false)
+	agent.StringFlag("keep", "", "warning", "lowest severity accepted below -low: all, debug, info, warning, error, fatal or none", false)
+	agent.StringFlag("format", "", "raw", "format of persisted lines: raw, json or text", false)
+	agent.StringFlag("layout", "", scribe.DefaultLayout, "layout of persisted lines for the text format", false)
+	agent.StringFlag("ack", "", "received", "default acknowledgement of requests: received, written or synced", false)
+	agent.StringFlag("flush", "", scribe.FlushLine, "when lines are written to files: line, size or interval", false)
+	agent.StringFlag("buffer", "", "64KB", "size of the buffer of every open file for the size and interval flush", false)
+	agent.StringFlag("flushint", "", "1s", "time between flushes for the interval flush", false)
+	agent.StringFlag("sync", "", scribe.SyncNever, "when files are synced to disk: never, interval or always", false)
+	agent.StringFlag("syncint", "", "1s", "time between syncs for the interval sync", false)
+	agent.StringFlag("spool", "", "", "directory of the spool keeping accepted requests until written, i.e. ../spool", false)
+	agent.IntFlag("workers", "", 0, "number of workers writing files in parallel, 0 for the number of CPUs", false)
+	agent.IntFlag("queue", "", 64, "number of requests queued for every worker", false)
+	agent.StringFlag("onerror", "", scribe.OnErrorSkip, "what happens to requests failed to be written: skip, retry or deadletter", false)
+	agent.IntFlag("retries", "", 3, "number of retries of failed writes for the retry and deadletter policies", false)
+	agent.StringFlag("backoff", "", "100ms", "time before the first retry of a failed write, doubling with every retry", false)
+	agent.IntFlag("hport", "", 0, "port for the HTTP endpoint receiving requests, 0 disables it", false)
+	agent.StringFlag("sudp", "", "", "address receiving syslog messages over UDP, i.e. :514", false)
+	agent.StringFlag("stcp", "", "", "address receiving syslog messages over TCP, i.e. :514", false)
+	agent.StringFlag("srule", "", service.DefaultSyslogRule, "file of syslog messages using {app}, {host} and {facility}", false)
+	agent.StringFlag("rtcp", "", "", "address receiving plain text lines over TCP, i.e. :7070", false)
+	agent.StringFlag("runix", "", "", "unix domain socket receiving plain text lines, i.e. /tmp/scribe.sock", false)
+	agent.StringFlag("compression", "", "", "compression of the registration calls to the mediator: gzip or none", false)
 	agent.StringFlag("crt", "", "", "host's certificate for secured connections", false)
 	agent.StringFlag("pk", "", "", "host's private key", false)
 	agent.StringFlag("ca", "", "", "certificate authority's certificate", false)
@@ -31,6 +65,8 @@ func init() {
 	med.IntFlag("port", "", 8000, "port for mediator server to listen to requests", false)
 	med.BoolFlag("pprof", "", "additional server for pprof functionality", false)
 	med.IntFlag("pport", "", 2222, "port for pprof server", false)
+	med.IntFlag("hport", "", 0, "port for the HTTP endpoint receiving requests, 0 disables it", false)
+	med.StringFlag("compression", "", "", "compression of the requests forwarded to scribes: gzip or none", false)
 	med.StringFlag("crt", "", "", "host's certificate for secured connections", false)
 	med.StringFlag("pk", "", "", "host's private key", false)
 	med.StringFlag("ca", "", "", "certificate authority's certificate", false)
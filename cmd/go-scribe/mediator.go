@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -53,10 +54,15 @@ func fillMediatorConfigFromFlags(flags map[string]string) (*types.MediatorConfig
 	if err != nil {
 		return nil, fmt.Errorf("failed to get the value of 'pprof' flag: %v", err)
 	}
+	hport, err := c.IntValue("hport", "mediator", flags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the value of 'hport' flag: %v", err)
+	}
+	compression := c.StringValue("compression", "mediator", flags)
 	crt := c.StringValue("crt", "mediator", flags)
 	pk := c.StringValue("pk", "mediator", flags)
 	ca := c.StringValue("ca", "mediator", flags)
-	m := &types.MediatorConfig{port, pprofInfo, pport,
+	m := &types.MediatorConfig{port, pprofInfo, pport, hport, compression,
 		types.CertificateConfig{crt, pk, ca}}
 	return m, nil
 }
@@ -84,13 +90,22 @@ func mediatorHandler(flags map[string]string) error {
 	stopAll := make(chan os.Signal, 1)
 	signal.Notify(stopAll, syscall.SIGTERM, syscall.SIGINT)
 
-	m, err := med.New(conf.Port, conf.Certificate, conf.PrivateKey, conf.CertificateAuthority)
+	m, err := med.New(conf.Port, conf.Certificate, conf.PrivateKey, conf.CertificateAuthority,
+		med.WithCompression(conf.Compression))
 	if err != nil {
 		return fmt.Errorf("failed to start a new mediator: %v", err)
 	}
 	defer m.Shutdown()
 	go m.Serve()
 
+	if conf.HTTPPort != 0 {
+		hsrv, err := serveHTTP(conf.HTTPPort, m.Logger(), conf.CertificateConfig)
+		if err != nil {
+			return err
+		}
+		defer hsrv.Shutdown(context.Background())
+	}
+
 	var srv *http.Server
 
 	if conf.Profile {
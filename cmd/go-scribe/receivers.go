@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+
+	p "github.com/RomanosTrechlis/go-scribe/internal/util/format/print"
+	"github.com/RomanosTrechlis/go-scribe/service"
+	"github.com/RomanosTrechlis/go-scribe/types"
+)
+
+// serveSyslog receives syslog messages on the
+// configured addresses and pushes them to the logger.
+func serveSyslog(conf *types.AgentConfig, l service.Logger) (*service.SyslogServer, error) {
+	s := service.NewSyslogServer(l, conf.SyslogRule)
+	if conf.SyslogUDP != "" {
+		if err := s.ListenUDP(conf.SyslogUDP); err != nil {
+			s.Close()
+			return nil, err
+		}
+		p.Print(fmt.Sprintf("Syslog receiver listens on udp %s", conf.SyslogUDP))
+	}
+	if conf.SyslogTCP != "" {
+		if err := s.ListenTCP(conf.SyslogTCP); err != nil {
+			s.Close()
+			return nil, err
+		}
+		p.Print(fmt.Sprintf("Syslog receiver listens on tcp %s", conf.SyslogTCP))
+	}
+	return s, nil
+}
+
+// serveRaw receives plain text lines on the
+// configured listeners and pushes them to the logger.
+func serveRaw(conf *types.AgentConfig, l service.Logger) (*service.RawServer, error) {
+	s := service.NewRawServer(l)
+	if conf.RawTCP != "" {
+		if err := s.ListenTCP(conf.RawTCP); err != nil {
+			s.Close()
+			return nil, err
+		}
+		p.Print(fmt.Sprintf("Raw receiver listens on tcp %s", conf.RawTCP))
+	}
+	if conf.RawUnix != "" {
+		if err := s.ListenUnix(conf.RawUnix); err != nil {
+			s.Close()
+			return nil, err
+		}
+		p.Print(fmt.Sprintf("Raw receiver listens on unix socket %s", conf.RawUnix))
+	}
+	return s, nil
+}
@@ -8,9 +8,11 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/RomanosTrechlis/go-scribe/internal/util/fs"
 	"github.com/RomanosTrechlis/go-scribe/scribe"
+	"github.com/RomanosTrechlis/go-scribe/service"
 	"github.com/RomanosTrechlis/go-scribe/types"
 	"gopkg.in/yaml.v2"
 )
@@ -27,9 +29,41 @@ func getAgentQs() []q {
 	qs = append(qs, q{6, "mediator", "Where is the Mediator, if any", "", -1})
 	qs = append(qs, q{7, "log_path", "Where should logs be written", "logs", -1})
 	qs = append(qs, q{8, "log_file_size", "What's the maximum size of log files should be", "10MB", -1})
-	qs = append(qs, q{9, "certificate", "Certificate's path", "", -1})
-	qs = append(qs, q{10, "private_key", "Private Key path", "", -1})
-	qs = append(qs, q{11, "certificate_authority", "Certificate Authority path", "", -1})
+	qs = append(qs, q{9, "rotation", "When should log files rotate besides size (none, hourly, daily or a duration)", scribe.RotateNone, -1})
+	qs = append(qs, q{10, "timezone", "Which timezone defines the rotation times, i.e. UTC", "", 9})
+	qs = append(qs, q{11, "segment_compression", "How should rotated log files be compressed (gzip, none)", scribe.CompressNone, -1})
+	qs = append(qs, q{12, "retention_age", "How old should rotated log files get before deleted, 0s for ever", "0s", -1})
+	qs = append(qs, q{13, "retention_segments", "How many rotated log files should be kept for every file, 0 for all", "0", -1})
+	qs = append(qs, q{14, "retention_bytes", "What's the disk budget of every path, if any", "", -1})
+	qs = append(qs, q{15, "retention_interval", "How often should rotated log files be deleted", "1m", -1})
+	qs = append(qs, q{16, "retention_dry_run", "Should deletions only be reported", "false", -1})
+	qs = append(qs, q{17, "disk_low", "Below how much free space should requests be rejected, if at all", "", -1})
+	qs = append(qs, q{18, "disk_high", "At how much free space should every request be accepted again", "", 17})
+	qs = append(qs, q{19, "disk_keep", "What's the lowest severity accepted while free space is low (all, debug, info, warning, error, fatal, none)", "warning", 17})
+	qs = append(qs, q{20, "log_format", "What's the format of log lines (raw, json, text)", "raw", -1})
+	qs = append(qs, q{21, "log_layout", "What's the layout of log lines for the text format", scribe.DefaultLayout, 20})
+	qs = append(qs, q{22, "ack_mode", "When should requests be acknowledged (received, written, synced)", "received", -1})
+	qs = append(qs, q{23, "flush_policy", "When should lines be written to files (line, size, interval)", scribe.FlushLine, -1})
+	qs = append(qs, q{24, "buffer_size", "What's the size of the buffer of every open file", "64KB", 23})
+	qs = append(qs, q{25, "flush_interval", "How often should buffered lines be written for the interval policy", "1s", 23})
+	qs = append(qs, q{26, "sync_policy", "When should files be synced to disk (never, interval, always)", scribe.SyncNever, -1})
+	qs = append(qs, q{27, "sync_interval", "How often should files be synced for the interval policy", "1s", 26})
+	qs = append(qs, q{28, "spool_path", "Where should accepted requests be spooled until written, if anywhere", "", -1})
+	qs = append(qs, q{29, "workers", "How many workers should write files in parallel, 0 for the number of CPUs", "0", -1})
+	qs = append(qs, q{30, "queue_depth", "How many requests should be queued for every worker", "64", -1})
+	qs = append(qs, q{31, "on_error", "What should happen to requests failed to be written (skip, retry, deadletter)", scribe.OnErrorSkip, -1})
+	qs = append(qs, q{32, "retries", "How many times should failed writes be retried", "3", 31})
+	qs = append(qs, q{33, "backoff", "How long before the first retry of a failed write", "100ms", 31})
+	qs = append(qs, q{34, "http_port", "What is Agent's HTTP port, 0 for none", "0", -1})
+	qs = append(qs, q{35, "syslog_udp", "Where should syslog messages over UDP be received, if anywhere", "", -1})
+	qs = append(qs, q{36, "syslog_tcp", "Where should syslog messages over TCP be received, if anywhere", "", -1})
+	qs = append(qs, q{37, "syslog_rule", "How should files of syslog messages be named", service.DefaultSyslogRule, -1})
+	qs = append(qs, q{38, "raw_tcp", "Where should plain text lines over TCP be received, if anywhere", "", -1})
+	qs = append(qs, q{39, "raw_unix", "Which unix socket should receive plain text lines, if any", "", -1})
+	qs = append(qs, q{40, "compression", "How should the registration calls to the Mediator be compressed (gzip, none)", "none", -1})
+	qs = append(qs, q{41, "certificate", "Certificate's path", "", -1})
+	qs = append(qs, q{42, "private_key", "Private Key path", "", -1})
+	qs = append(qs, q{43, "certificate_authority", "Certificate Authority path", "", -1})
 	return qs
 }
 
@@ -80,6 +114,176 @@ func (ac *AgentConfig) addValueToAgentConfig(field, val string) error {
 		}
 		ac.LogFileSize = size
 	}
+	if field == "rotation" {
+		ac.Rotation = val
+	}
+	if field == "timezone" {
+		ac.Timezone = val
+	}
+	if field == "segment_compression" {
+		ac.SegmentCompression = val
+	}
+	if field == "retention_age" {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+		ac.RetentionAge = d
+	}
+	if field == "retention_segments" {
+		v, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+		ac.RetentionSegments = v
+	}
+	if field == "retention_bytes" && val != "" {
+		size, err := scribe.LexicalToNumber(val)
+		if err != nil {
+			return err
+		}
+		ac.RetentionBytes = size
+	}
+	if field == "disk_low" && val != "" {
+		size, err := scribe.LexicalToNumber(val)
+		if err != nil {
+			return err
+		}
+		ac.DiskLow = size
+	}
+	if field == "disk_high" && val != "" {
+		size, err := scribe.LexicalToNumber(val)
+		if err != nil {
+			return err
+		}
+		ac.DiskHigh = size
+	}
+	if field == "disk_keep" {
+		if _, err := scribe.ParseKeep(val); err != nil {
+			return err
+		}
+		ac.DiskKeep = val
+	}
+	if field == "retention_interval" {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+		ac.RetentionInterval = d
+	}
+	if field == "retention_dry_run" {
+		v, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		ac.RetentionDryRun = v
+	}
+	if field == "log_format" {
+		if _, err := scribe.NewFormatter(val, ""); err != nil {
+			return err
+		}
+		ac.LogFormat = val
+	}
+	if field == "log_layout" {
+		ac.LogLayout = val
+	}
+	if field == "ack_mode" {
+		if _, err := scribe.ParseAck(val); err != nil {
+			return err
+		}
+		ac.AckMode = val
+	}
+	if field == "flush_policy" {
+		ac.FlushPolicy = val
+	}
+	if field == "buffer_size" {
+		size, err := scribe.LexicalToNumber(val)
+		if err != nil {
+			return err
+		}
+		ac.BufferSize = size
+	}
+	if field == "flush_interval" {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+		ac.FlushInterval = d
+	}
+	if field == "sync_policy" {
+		ac.SyncPolicy = val
+	}
+	if field == "sync_interval" {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+		ac.SyncInterval = d
+	}
+	if field == "spool_path" {
+		ac.SpoolPath = val
+	}
+	if field == "workers" {
+		v, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+		ac.Workers = v
+	}
+	if field == "queue_depth" {
+		v, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+		ac.QueueDepth = v
+	}
+	if field == "on_error" {
+		switch val {
+		case scribe.OnErrorSkip, scribe.OnErrorRetry, scribe.OnErrorDeadLetter:
+		default:
+			return fmt.Errorf("unknown error policy '%s'", val)
+		}
+		ac.OnError = val
+	}
+	if field == "retries" {
+		v, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+		ac.Retries = v
+	}
+	if field == "backoff" {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+		ac.Backoff = d
+	}
+	if field == "http_port" {
+		v, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+		ac.HTTPPort = v
+	}
+	if field == "syslog_udp" {
+		ac.SyslogUDP = val
+	}
+	if field == "syslog_tcp" {
+		ac.SyslogTCP = val
+	}
+	if field == "syslog_rule" {
+		ac.SyslogRule = val
+	}
+	if field == "raw_tcp" {
+		ac.RawTCP = val
+	}
+	if field == "raw_unix" {
+		ac.RawUnix = val
+	}
+	if field == "compression" {
+		ac.Compression = val
+	}
 
 	if field == "certificate" {
 		ac.Certificate = val
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/user"
@@ -17,6 +18,8 @@ import (
 	"github.com/RomanosTrechlis/go-scribe/internal/util/fs"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/yaml.v2"
 )
 
@@ -28,11 +31,52 @@ It connects with gRPC to the mediator service and gets the version number.
 `
 
 	statsShortDesc = "stats command returns how many requests each scribe handled"
-	statsLongDesc  = "stats command returns how many requests each scribe handled"
+	statsLongDesc  = "stats command returns how many requests each scribe handled, its free space, whether it is degraded for lack of space and how many requests it rejected while degraded"
+
+	filesShortDesc = "files command lists the log files of every scribe"
+	filesLongDesc  = `files command lists the log files of every scribe.
+
+For every file it prints the size of the current file, the number
+of rotated files, the size of all of them and the last write time.
+`
+
+	retentionShortDesc = "retention command lists the rotated files deleted by every scribe"
+	retentionLongDesc  = `retention command lists the rotated files deleted by every scribe.
+
+Scribes delete the rotated files exceeding their retention policy, by
+age, count or disk budget, in the background. For every deletion it
+prints the file, its size, the reason and the time. Files of scribes
+in dry run mode are only reported. The run flag enforces the policies
+before listing.
+`
 
 	respShortDesc = "resp command returns every scribe's filename responsibility"
 	respLongDesc  = "resp command returns every scribe's filename responsibility"
 
+	tailShortDesc = "tail command follows a log file as it gets written"
+	tailLongDesc  = `tail command follows a log file as it gets written.
+
+It connects with gRPC to the mediator, or a scribe, and prints the
+last lines of the file followed by every new line, like 'tail -f'.
+`
+
+	readShortDesc = "read command prints the lines of a log file across its rotated files"
+	readLongDesc  = `read command prints the lines of a log file across its rotated files.
+
+It connects with gRPC to the mediator, or a scribe, and prints a page
+of the lines of the file, oldest first. The since and until flags,
+in RFC3339 format, select the lines written during that time range,
+by their own time or, for lines without one, by their file's.
+`
+
+	searchShortDesc = "search command prints the lines matching a query on every scribe"
+	searchLongDesc  = `search command prints the lines matching a query on every scribe.
+
+It connects with gRPC to the mediator, which searches the files of every
+scribe, or to a single scribe. The query is a substring unless the regex
+flag is set. Every line is printed as scribe:path/filename:segment:line_number.
+`
+
 	createShortDesc = "create command is used for creating config files"
 	createLongDesc  = `create command is used as an interactive assistance for creating
 various configuration files.
@@ -50,6 +94,7 @@ const (
 	SCRIBE_CONFIG   = "scribe_config.yml"
 
 	THE_ANSWER_TO_EVERYTHING = 4242
+	MEDIATOR_PORT            = 8000
 )
 
 func main() {
@@ -123,8 +168,38 @@ func createCommandTree(host string) *cli.CLI {
 
 	c.New("stats", statsShortDesc, statsLongDesc, getStatsHandler(host))
 
+	files := c.New("files", filesShortDesc, filesLongDesc, getFilesHandler(host, c))
+	files.StringFlag("p", "path", "", "list only the files under this path", false)
+
+	retention := c.New("retention", retentionShortDesc, retentionLongDesc, getRetentionHandler(host, c))
+	retention.BoolFlag("r", "run", "enforces the retention policies first", false)
+
 	c.New("resp", respShortDesc, respLongDesc, getRespHandler(host))
 
+	tail := c.New("tail", tailShortDesc, tailLongDesc, getTailHandler(host, c))
+	tail.StringFlag("f", "filename", "", "the file to follow", true)
+	tail.StringFlag("p", "path", "", "the path of the file", false)
+	tail.IntFlag("n", "lines", 10, "number of already written lines to print first", false)
+	tail.StringFlag("a", "addr", "", "address of the mediator, or scribe, gRPC server (default host:8000)", false)
+
+	read := c.New("read", readShortDesc, readLongDesc, getReadHandler(host, c))
+	read.StringFlag("f", "filename", "", "the file to read", true)
+	read.StringFlag("p", "path", "", "the path of the file", false)
+	read.IntFlag("o", "offset", 0, "number of lines to skip", false)
+	read.IntFlag("l", "limit", 100, "maximum number of lines to print", false)
+	read.StringFlag("s", "since", "", "read lines written after this time, i.e. 2018-09-01T10:00:00Z", false)
+	read.StringFlag("u", "until", "", "read lines written before this time, i.e. 2018-09-02T10:00:00Z", false)
+	read.StringFlag("a", "addr", "", "address of the mediator, or scribe, gRPC server (default host:8000)", false)
+
+	search := c.New("search", searchShortDesc, searchLongDesc, getSearchHandler(host, c))
+	search.StringFlag("q", "query", "", "the substring, or regular expression, to search for", true)
+	search.BoolFlag("r", "regex", "the query is a regular expression", false)
+	search.StringFlag("p", "path", "", "search only the files under this path", false)
+	search.IntFlag("l", "limit", 100, "maximum number of lines to print", false)
+	search.StringFlag("s", "since", "", "search lines written after this time, i.e. 2018-09-01T10:00:00Z", false)
+	search.StringFlag("u", "until", "", "search lines written before this time, i.e. 2018-09-02T10:00:00Z", false)
+	search.StringFlag("a", "addr", "", "address of the mediator, or scribe, gRPC server (default host:8000)", false)
+
 	create := c.New("create", createShortDesc, createLongDesc, getCreateHandler(c))
 	create.StringFlag("t", "type", "cli", "prints the configuration on the stdout. Types: mediator, scribe, cli", true)
 	create.BoolFlag("w", "write", "write creates the config file under .scribe directory", false)
@@ -153,6 +228,23 @@ func getCreateHandler(c *cli.CLI) func(flags map[string]string) error {
 	}
 }
 
+// describe explains the gRPC status of an error returned by a service.
+func describe(err error) string {
+	st := status.Convert(err)
+	switch st.Code() {
+	case codes.Unavailable:
+		return fmt.Sprintf("service is unavailable, check that it is running: %s", st.Message())
+	case codes.DeadlineExceeded:
+		return fmt.Sprintf("service didn't respond in time: %s", st.Message())
+	case codes.Unimplemented:
+		return fmt.Sprintf("command is not supported by this service: %s", st.Message())
+	case codes.InvalidArgument:
+		return fmt.Sprintf("invalid request: %s", st.Message())
+	default:
+		return fmt.Sprintf("%s: %s", st.Code(), st.Message())
+	}
+}
+
 func getUserHomeDir() (string, error) {
 	usr, err := user.Current()
 	if err != nil {
@@ -208,7 +300,7 @@ func getRespHandler(host string) func(flags map[string]string) error {
 		client := pb.NewCLIScribeClient(conn)
 		res, err := client.GetScribesResponsibility(context.Background(), &pb.ResponsibilityRequest{})
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "failed to get response from mediator service: %v", err)
+			fmt.Fprintf(os.Stderr, "failed to get response from mediator service: %s", describe(err))
 			os.Exit(2)
 		}
 
@@ -224,6 +316,167 @@ func getRespHandler(host string) func(flags map[string]string) error {
 	}
 }
 
+func getTailHandler(host string, c *cli.CLI) func(flags map[string]string) error {
+	return func(flags map[string]string) error {
+		n, err := c.IntValue("n", "tail", flags)
+		if err != nil {
+			return fmt.Errorf("failed to get flag: %v", err)
+		}
+		addr := c.StringValue("a", "tail", flags)
+		if addr == "" {
+			addr = fmt.Sprintf("%s:%d", host, MEDIATOR_PORT)
+		}
+		conn, err := grpc.Dial(addr,
+			grpc.WithInsecure(),
+			grpc.WithTimeout(1*time.Second))
+		if err != nil {
+			return fmt.Errorf("did not connect: %v\n", err)
+		}
+		defer conn.Close()
+
+		client := pb.NewLogReaderClient(conn)
+		stream, err := client.Tail(context.Background(), &pb.TailRequest{
+			Filename: c.StringValue("f", "tail", flags),
+			Path:     c.StringValue("p", "tail", flags),
+			Lines:    int32(n),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to follow file: %s", describe(err))
+		}
+		for {
+			l, err := stream.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("stopped following file: %s", describe(err))
+			}
+			fmt.Println(l.GetLine())
+		}
+	}
+}
+
+func getReadHandler(host string, c *cli.CLI) func(flags map[string]string) error {
+	return func(flags map[string]string) error {
+		offset, err := c.IntValue("o", "read", flags)
+		if err != nil {
+			return fmt.Errorf("failed to get flag: %v", err)
+		}
+		limit, err := c.IntValue("l", "read", flags)
+		if err != nil {
+			return fmt.Errorf("failed to get flag: %v", err)
+		}
+		from, err := parseTime(c.StringValue("s", "read", flags))
+		if err != nil {
+			return fmt.Errorf("failed to parse since flag: %v", err)
+		}
+		to, err := parseTime(c.StringValue("u", "read", flags))
+		if err != nil {
+			return fmt.Errorf("failed to parse until flag: %v", err)
+		}
+		addr := c.StringValue("a", "read", flags)
+		if addr == "" {
+			addr = fmt.Sprintf("%s:%d", host, MEDIATOR_PORT)
+		}
+		conn, err := grpc.Dial(addr,
+			grpc.WithInsecure(),
+			grpc.WithTimeout(1*time.Second))
+		if err != nil {
+			return fmt.Errorf("did not connect: %v\n", err)
+		}
+		defer conn.Close()
+
+		client := pb.NewLogReaderClient(conn)
+		res, err := client.Read(context.Background(), &pb.ReadRequest{
+			Filename: c.StringValue("f", "read", flags),
+			Path:     c.StringValue("p", "read", flags),
+			Offset:   int64(offset),
+			Limit:    int32(limit),
+			From:     from,
+			To:       to,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read file: %s", describe(err))
+		}
+		for _, l := range res.GetLines() {
+			fmt.Println(l.GetLine())
+		}
+		if res.GetMore() {
+			fmt.Fprintf(os.Stderr, "there are more lines, use '-o %d' to read them\n", res.GetNextOffset())
+		}
+		return nil
+	}
+}
+
+func getSearchHandler(host string, c *cli.CLI) func(flags map[string]string) error {
+	return func(flags map[string]string) error {
+		limit, err := c.IntValue("l", "search", flags)
+		if err != nil {
+			return fmt.Errorf("failed to get flag: %v", err)
+		}
+		regex, err := c.BoolValue("r", "search", flags)
+		if err != nil {
+			return fmt.Errorf("failed to get flag: %v", err)
+		}
+		from, err := parseTime(c.StringValue("s", "search", flags))
+		if err != nil {
+			return fmt.Errorf("failed to parse since flag: %v", err)
+		}
+		to, err := parseTime(c.StringValue("u", "search", flags))
+		if err != nil {
+			return fmt.Errorf("failed to parse until flag: %v", err)
+		}
+		addr := c.StringValue("a", "search", flags)
+		if addr == "" {
+			addr = fmt.Sprintf("%s:%d", host, MEDIATOR_PORT)
+		}
+		conn, err := grpc.Dial(addr,
+			grpc.WithInsecure(),
+			grpc.WithTimeout(1*time.Second))
+		if err != nil {
+			return fmt.Errorf("did not connect: %v\n", err)
+		}
+		defer conn.Close()
+
+		client := pb.NewLogReaderClient(conn)
+		stream, err := client.Search(context.Background(), &pb.SearchRequest{
+			Query:      c.StringValue("q", "search", flags),
+			Regex:      regex,
+			PathPrefix: c.StringValue("p", "search", flags),
+			Limit:      int32(limit),
+			From:       from,
+			To:         to,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to search: %s", describe(err))
+		}
+		for {
+			r, err := stream.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to search: %s", describe(err))
+			}
+			fmt.Printf("%s:%s:%s:%d: %s\n", r.GetScribe(), filepath.Join(r.GetPath(), r.GetFilename()),
+				r.GetSegment(), r.GetLineNumber(), r.GetLine())
+		}
+	}
+}
+
+// parseTime parses an RFC3339 time to nanoseconds since epoch,
+// an empty value is zero.
+func parseTime(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, err
+	}
+	return t.UnixNano(), nil
+}
+
 func getStatsHandler(host string) func(flags map[string]string) error {
 	return func(flags map[string]string) error {
 		conn, err := grpc.Dial(fmt.Sprintf("%s:%d", host, THE_ANSWER_TO_EVERYTHING),
@@ -237,15 +490,87 @@ func getStatsHandler(host string) func(flags map[string]string) error {
 		client := pb.NewCLIScribeClient(conn)
 		res, err := client.GetStats(context.Background(), &pb.StatsRequest{})
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "failed to get response from mediator service: %v", err)
+			fmt.Fprintf(os.Stderr, "failed to get response from mediator service: %s", describe(err))
 			os.Exit(2)
 		}
 
 		buf := new(bytes.Buffer)
 		w := tabwriter.NewWriter(buf, 0, 0, 1, ' ', tabwriter.DiscardEmptyColumns)
-		fmt.Fprint(w, "Name\tCount\n")
+		fmt.Fprint(w, "Name\tCount\tFree\tDegraded\tShed\tFailed\tRetried\tSkipped\tDeadLettered\n")
 		for _, v := range res.Result {
-			fmt.Fprintf(w, "%s\t%d\n", v.Name, v.Count)
+			fmt.Fprintf(w, "%s\t%d\t%d\t%v\t%d\t%d\t%d\t%d\t%d\n", v.Name, v.Count, v.FreeBytes, v.Degraded, v.Shed,
+				v.Failed, v.Retried, v.Skipped, v.DeadLettered)
+		}
+		w.Flush()
+		fmt.Println(string(buf.Bytes()))
+		return nil
+	}
+}
+
+func getFilesHandler(host string, c *cli.CLI) func(flags map[string]string) error {
+	return func(flags map[string]string) error {
+		conn, err := grpc.Dial(fmt.Sprintf("%s:%d", host, THE_ANSWER_TO_EVERYTHING),
+			grpc.WithInsecure(),
+			grpc.WithTimeout(1*time.Second))
+		if err != nil {
+			return fmt.Errorf("did not connect: %v\n", err)
+		}
+		defer conn.Close()
+
+		client := pb.NewCLIScribeClient(conn)
+		res, err := client.ListFiles(context.Background(), &pb.ListFilesRequest{
+			PathPrefix: c.StringValue("p", "files", flags),
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to get response from mediator service: %s", describe(err))
+			os.Exit(2)
+		}
+
+		buf := new(bytes.Buffer)
+		w := tabwriter.NewWriter(buf, 0, 0, 1, ' ', tabwriter.DiscardEmptyColumns)
+		fmt.Fprint(w, "Scribe\tFile\tSize\tSegments\tTotal\tLast Write\n")
+		for _, v := range res.Files {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%s\n", v.Scribe, filepath.Join(v.Path, v.Filename),
+				v.Size, v.Segments, v.TotalBytes, time.Unix(0, v.LastWrite).Format(time.RFC3339))
+		}
+		w.Flush()
+		fmt.Println(string(buf.Bytes()))
+		return nil
+	}
+}
+
+func getRetentionHandler(host string, c *cli.CLI) func(flags map[string]string) error {
+	return func(flags map[string]string) error {
+		run, err := c.BoolValue("r", "retention", flags)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to get flag: %v", err)
+			os.Exit(2)
+		}
+		conn, err := grpc.Dial(fmt.Sprintf("%s:%d", host, THE_ANSWER_TO_EVERYTHING),
+			grpc.WithInsecure(),
+			grpc.WithTimeout(1*time.Second))
+		if err != nil {
+			return fmt.Errorf("did not connect: %v\n", err)
+		}
+		defer conn.Close()
+
+		client := pb.NewCLIScribeClient(conn)
+		res, err := client.GetRetention(context.Background(), &pb.RetentionRequest{Run: run})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to get response from mediator service: %s", describe(err))
+			os.Exit(2)
+		}
+
+		buf := new(bytes.Buffer)
+		w := tabwriter.NewWriter(buf, 0, 0, 1, ' ', tabwriter.DiscardEmptyColumns)
+		fmt.Fprint(w, "Scribe\tFile\tSegment\tSize\tReason\tDeleted\n")
+		for _, v := range res.Deletions {
+			deleted := time.Unix(0, v.Time).Format(time.RFC3339)
+			if v.DryRun {
+				deleted = "dry run"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", v.Scribe, filepath.Join(v.Path, v.Filename),
+				v.Segment, v.Size, v.Reason, deleted)
 		}
 		w.Flush()
 		fmt.Println(string(buf.Bytes()))
@@ -271,7 +596,7 @@ func getVersionHandler(host string, c *cli.CLI) func(flags map[string]string) er
 		client := pb.NewCLIScribeClient(conn)
 		res, err := client.GetVersion(context.Background(), &pb.VersionRequest{All: a})
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "failed to get response from mediator service: %v", err)
+			fmt.Fprintf(os.Stderr, "failed to get response from mediator service: %s", describe(err))
 			os.Exit(2)
 		}
 
@@ -29,9 +29,11 @@ func getMediatorQs() []q {
 	qs = append(qs, q{1, "port", "What is Mediator's port", "8000", -1})
 	qs = append(qs, q{2, "profile", "Does Mediator provides profile info", "false", -1})
 	qs = append(qs, q{3, "profile_port", "What is Mediator's profile port", "2222", 2})
-	qs = append(qs, q{4, "certificate", "Certificate's path", "", -1})
-	qs = append(qs, q{5, "private_key", "Private Key path", "", -1})
-	qs = append(qs, q{6, "certificate_authority", "Certificate Authority path", "", -1})
+	qs = append(qs, q{4, "http_port", "What is Mediator's HTTP port, 0 for none", "0", -1})
+	qs = append(qs, q{5, "compression", "How should requests forwarded to Scribes be compressed (gzip, none)", "none", -1})
+	qs = append(qs, q{6, "certificate", "Certificate's path", "", -1})
+	qs = append(qs, q{7, "private_key", "Private Key path", "", -1})
+	qs = append(qs, q{8, "certificate_authority", "Certificate Authority path", "", -1})
 	return qs
 }
 
@@ -58,6 +60,16 @@ func (mc *MediatorConfig) addValueToMediatorConfig(field, val string) error {
 		}
 		mc.ProfilePort = v
 	}
+	if field == "http_port" {
+		v, err := strconv.Atoi(val)
+		if err != nil {
+			return err
+		}
+		mc.HTTPPort = v
+	}
+	if field == "compression" {
+		mc.Compression = val
+	}
 	if field == "certificate" {
 		mc.Certificate = val
 	}
@@ -5,6 +5,7 @@ require (
 	github.com/golang/protobuf v1.2.0
 	github.com/rs/xid v1.2.1
 	golang.org/x/net v0.0.0-20180911220305-26e67e76b6c3
+	google.golang.org/genproto v0.0.0-20180817151627-c66870c02cf8
 	google.golang.org/grpc v1.15.0
 	gopkg.in/yaml.v2 v2.2.1
 )
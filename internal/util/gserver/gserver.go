@@ -8,19 +8,41 @@ import (
 	"io/ioutil"
 	"log"
 	"net"
+	"net/http"
+	"os"
 
 	p "github.com/RomanosTrechlis/go-scribe/internal/util/format/print"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding"
+	// registers the gzip compressor, so that servers accept compressed
+	// requests and reply compressed to the clients compressing theirs
+	_ "google.golang.org/grpc/encoding/gzip"
 	"google.golang.org/grpc/reflection"
 )
 
 // New creates a grpc server with or without SSL
 func New(crt, key, ca string) (*grpc.Server, error) {
-	if crt == "" || key == "" || ca == "" {
+	config, err := TLSConfig(crt, key, ca)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
 		return grpc.NewServer(), nil
 	}
 
+	p.Print("Log streamer will start with TLS")
+	// Create the gRPC server with the credentials
+	return grpc.NewServer(grpc.Creds(credentials.NewTLS(config))), nil
+}
+
+// TLSConfig creates the configuration of a 2-way-SSL server,
+// it is nil when the certificates are not given.
+func TLSConfig(crt, key, ca string) (*tls.Config, error) {
+	if crt == "" || key == "" || ca == "" {
+		return nil, nil
+	}
+
 	// one way ssl
 	if crt != "" && key != "" && ca == "" {
 		// todo(romanos): yet to be implemented
@@ -45,16 +67,47 @@ func New(crt, key, ca string) (*grpc.Server, error) {
 		return nil, fmt.Errorf("failed to append client certs")
 	}
 
-	// Create the TLS credentials
-	creds := credentials.NewTLS(&tls.Config{
+	return &tls.Config{
 		ClientAuth:   tls.RequireAndVerifyClientCert,
 		Certificates: []tls.Certificate{certificate},
 		ClientCAs:    certPool,
-	})
+	}, nil
+}
 
-	p.Print("Log streamer will start with TLS")
-	// Create the gRPC server with the credentials
-	return grpc.NewServer(grpc.Creds(creds)), nil
+// DialCompression returns the dial options compressing every call with
+// the named compressor, i.e. gzip. No compression is either empty or none.
+func DialCompression(name string) ([]grpc.DialOption, error) {
+	if name == "" || name == "none" {
+		return nil, nil
+	}
+	if encoding.GetCompressor(name) == nil {
+		return nil, fmt.Errorf("unknown compression '%s'", name)
+	}
+	return []grpc.DialOption{grpc.WithDefaultCallOptions(grpc.UseCompressor(name))}, nil
+}
+
+// ServeHTTP serves the handler over HTTP, or HTTPS
+// when the certificates are given.
+func ServeHTTP(addr string, h http.Handler, crt, key, ca string) (*http.Server, error) {
+	config, err := TLSConfig(crt, key, ca)
+	if err != nil {
+		return nil, err
+	}
+	srv := &http.Server{Addr: addr, Handler: h, TLSConfig: config}
+
+	go func() {
+		var err error
+		if config != nil {
+			p.Print("HTTP server will start with TLS")
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			fmt.Fprintf(os.Stderr, "http server failed: %v\n", err)
+		}
+	}()
+	return srv, nil
 }
 
 // Serve listen for LogRequests
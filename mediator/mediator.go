@@ -2,7 +2,8 @@ package mediator
 
 import (
 	"fmt"
-	"math/rand"
+	"io"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -13,12 +14,33 @@ import (
 	"github.com/RomanosTrechlis/go-scribe/service"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
+// responsibilityChars are the first characters of the filenames,
+// divided in consecutive ranges among the scribes
+const responsibilityChars = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// healthCheckTimeout is the time a scribe has to answer a health check
+const healthCheckTimeout = 2 * time.Second
+
+// forwardTimeout is the time a scribe has to take a forwarded request,
+// and to write it when the request waits for the write
+const forwardTimeout = 10 * time.Second
+
+// scribeStream is an open LogStream to a scribe
+type scribeStream struct {
+	pb.LogScribe_LogStreamClient
+	// cancel ends the stream, i.e. when a send takes too long
+	cancel context.CancelFunc
+}
+
 // Mediator grpc server and other relative info
 type Mediator struct {
-	// mux protects scribes and scribesCon
-	// while pinging subscribers.
+	// mux protects scribes, scribesCon and the rest of the maps.
+	// It is never held while calling a scribe to forward requests.
 	mux sync.Mutex
 	// scribes has as key the scribe id
 	// and value its address.
@@ -32,9 +54,20 @@ type Mediator struct {
 	// streamResponsibility has as key a character
 	// and value a scribe id
 	scribeResponsibility map[string]string
+	// scribesStream has as key the scribe id
+	// and value an open LogStream used for forwarding requests.
+	scribesStream map[string]*scribeStream
+	// serving has as key the scribe id and value whether,
+	// according to its health service, it accepts requests.
+	serving map[string]bool
+
+	// health reports the serving status of the services
+	health *service.Health
+	// dialOptions are used for the connections to the scribes
+	dialOptions []grpc.DialOption
 
-	// input stream of protobuf requests
-	stream chan pb.LogRequest
+	// input stream of protobuf request batches
+	stream chan service.Entry
 
 	gRPC gserver.GRPC
 
@@ -49,27 +82,82 @@ type Info struct {
 	ScribeResponsibility map[string]string
 }
 
+// GetInfo returns copies of the scribes known to the mediator
 func (m *Mediator) GetInfo() Info {
-	return Info{m.scribes, m.scribesCounter, m.scribeResponsibility}
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	info := Info{
+		Scribes:              make(map[string]string, len(m.scribes)),
+		ScribesCounter:       make(map[string]int64, len(m.scribesCounter)),
+		ScribeResponsibility: make(map[string]string, len(m.scribeResponsibility)),
+	}
+	for k, v := range m.scribes {
+		info.Scribes[k] = v
+	}
+	for k, v := range m.scribesCounter {
+		info.ScribesCounter[k] = v
+	}
+	for k, v := range m.scribeResponsibility {
+		info.ScribeResponsibility[k] = v
+	}
+	return info
+}
+
+// subscribe registers the scribe at addr, replacing
+// a scribe with the same id at another address.
+func (m *Mediator) subscribe(id, addr string) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if old, ok := m.scribes[id]; ok && old != addr {
+		m.deregister(id, old)
+	}
+	m.scribes[id] = addr
+}
+
+// Option configures optional behaviour of a Mediator
+type Option func(m *Mediator) error
+
+// WithCompression compresses the requests forwarded to the
+// scribes with the named compressor, i.e. gzip.
+func WithCompression(name string) Option {
+	return func(m *Mediator) error {
+		opts, err := gserver.DialCompression(name)
+		if err != nil {
+			return err
+		}
+		m.dialOptions = append(m.dialOptions, opts...)
+		return nil
+	}
 }
 
 // New creates a new mediator
-func New(port int, crt, key, ca string) (*Mediator, error) {
+func New(port int, crt, key, ca string, opts ...Option) (*Mediator, error) {
 	srv, err := gserver.New(crt, key, ca)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create grpc server: %v", err)
 	}
 
 	m := &Mediator{
-		stream: make(chan pb.LogRequest),
+		stream: make(chan service.Entry),
 		gRPC: gserver.GRPC{
 			Server: srv,
 			Port:   port,
+			Stop:   make(chan struct{}),
 		},
 		scribesCon:           make(map[string]*grpc.ClientConn),
 		scribes:              make(map[string]string),
 		scribeResponsibility: make(map[string]string),
+		scribesStream:        make(map[string]*scribeStream),
+		serving:              make(map[string]bool),
+		health:               service.NewHealth(service.LogScribeService, service.LogReaderService, service.RegisterService),
 	}
+	for _, opt := range opts {
+		if err := opt(m); err != nil {
+			return nil, err
+		}
+	}
+	// there are no scribes to forward the requests to, yet
+	m.reportHealth()
 	return m, nil
 }
 
@@ -78,25 +166,188 @@ func (m *Mediator) serviceHandler(stop chan struct{}) {
 	for {
 		select {
 		case req := <-m.stream:
-			conn, err := m.getConnection(req.GetFilename())
+			err := m.forward(req)
+			if req.Done != nil {
+				req.Done <- err
+			}
 			if err != nil {
 				p.Print(err.Error())
 			}
-
-			client := pb.NewLogScribeClient(conn)
-			client.Log(context.Background(), &req)
-			m.counter++
+			m.counter += int64(len(req.Batch.GetEntries()))
 		case <-stop:
+			m.closeStreams()
 			return
 		}
 	}
 }
 
+// forward splits the batch by scribe responsibility and sends
+// each part to the responsible scribe. Single requests go through a
+// LogStream, which is opened on first use and kept for the next requests,
+// while bigger parts, and parts waiting for an acknowledgement of the write,
+// are sent with a single LogBatch call. Every part is sent, even when
+// another one fails, so a failed batch may have been partly forwarded,
+// which the error tells, and retrying it may write those parts twice.
+func (m *Mediator) forward(e service.Entry) error {
+	m.mux.Lock()
+	parts := m.split(e.Batch)
+	conns := make(map[string]*grpc.ClientConn, len(parts))
+	for id := range parts {
+		conns[id] = m.scribesCon[id]
+	}
+	m.mux.Unlock()
+
+	var failed error
+	sent := 0
+	for id, entries := range parts {
+		if err := m.forwardPart(id, conns[id], entries, e); err != nil {
+			if failed == nil {
+				failed = err
+			}
+			continue
+		}
+		sent += len(entries)
+	}
+	if failed != nil && sent > 0 {
+		st := status.Convert(failed)
+		return status.Errorf(st.Code(), "%s, %d of %d requests forwarded",
+			st.Message(), sent, len(e.Batch.GetEntries()))
+	}
+	return failed
+}
+
+// forwardPart sends the entries to the scribe within forwardTimeout
+func (m *Mediator) forwardPart(id string, conn *grpc.ClientConn, entries []*pb.LogRequest, e service.Entry) error {
+	if conn == nil {
+		return service.Unavailable(fmt.Sprintf("no scribe available for %d requests", len(entries)), 5*time.Second)
+	}
+	if len(entries) > 1 || e.Done != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), forwardTimeout)
+		defer cancel()
+		client := pb.NewLogScribeClient(conn)
+		_, err := client.LogBatch(ctx, &pb.LogBatch{Entries: entries, Ack: e.Ack})
+		if err != nil {
+			return forwardError(id, err)
+		}
+		return nil
+	}
+
+	stream, err := m.openStream(id, conn)
+	if err != nil {
+		return forwardError(id, err)
+	}
+	err = send(stream, entries[0])
+	if err == io.EOF {
+		// the scribe ended the stream rejecting an earlier request, whose
+		// status CloseAndRecv returns, and never received this one,
+		// so it is sent again through a new stream
+		if ended := closeStream(stream); ended != nil {
+			p.Print(forwardError(id, ended).Error())
+		}
+		m.dropStream(id, stream)
+		stream, err = m.openStream(id, conn)
+		if err != nil {
+			return forwardError(id, err)
+		}
+		err = send(stream, entries[0])
+	}
+	if err != nil {
+		m.dropStream(id, stream)
+		return forwardError(id, err)
+	}
+	return nil
+}
+
+// send sends the request through the stream, ending the stream
+// when the scribe doesn't take it within forwardTimeout.
+func send(stream *scribeStream, req *pb.LogRequest) error {
+	timer := time.AfterFunc(forwardTimeout, stream.cancel)
+	err := stream.Send(req)
+	if !timer.Stop() {
+		return status.Error(codes.DeadlineExceeded, "scribe didn't take the request in time")
+	}
+	return err
+}
+
+// closeStream closes the stream, waiting up to forwardTimeout for
+// the scribe to end it, and returns the status it ended with.
+func closeStream(stream *scribeStream) error {
+	timer := time.AfterFunc(forwardTimeout, stream.cancel)
+	_, err := stream.CloseAndRecv()
+	timer.Stop()
+	stream.cancel()
+	return err
+}
+
+// openStream returns the LogStream to the scribe, opening it on first use
+func (m *Mediator) openStream(id string, conn *grpc.ClientConn) (*scribeStream, error) {
+	m.mux.Lock()
+	stream, ok := m.scribesStream[id]
+	m.mux.Unlock()
+	if ok {
+		return stream, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := pb.NewLogScribeClient(conn)
+	s, err := client.LogStream(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	stream = &scribeStream{LogScribe_LogStreamClient: s, cancel: cancel}
+	m.mux.Lock()
+	m.scribesStream[id] = stream
+	m.mux.Unlock()
+	return stream, nil
+}
+
+// dropStream ends the stream to the scribe, unless replaced meanwhile
+func (m *Mediator) dropStream(id string, stream *scribeStream) {
+	m.mux.Lock()
+	if m.scribesStream[id] == stream {
+		delete(m.scribesStream, id)
+	}
+	m.mux.Unlock()
+	stream.cancel()
+}
+
+// forwardError keeps the status code of an error returned
+// by a scribe adding which scribe returned it.
+func forwardError(id string, err error) error {
+	st := status.Convert(err)
+	return status.Errorf(st.Code(), "failed to forward to scribe %s: %s", id, st.Message())
+}
+
+// split groups the entries of a batch by the id of the responsible scribe.
+// Callers must hold mux.
+func (m *Mediator) split(batch pb.LogBatch) map[string][]*pb.LogRequest {
+	parts := make(map[string][]*pb.LogRequest)
+	for _, e := range batch.GetEntries() {
+		id := m.getScribe(e.GetFilename())
+		parts[id] = append(parts[id], e)
+	}
+	return parts
+}
+
+// closeStreams closes every open LogStream to the scribes,
+// waiting up to forwardTimeout for every scribe to end it.
+func (m *Mediator) closeStreams() {
+	m.mux.Lock()
+	streams := m.scribesStream
+	m.scribesStream = make(map[string]*scribeStream)
+	m.mux.Unlock()
+	for id, stream := range streams {
+		if err := closeStream(stream); err != nil {
+			p.Print(fmt.Sprintf("failed to close stream to scribe %s: %v", id, err))
+		}
+	}
+}
+
 // Serve starts mediator server
 func (m *Mediator) Serve() {
 	p.Print("Log Mediator is starting...")
 	m.stopAll = make(chan struct{})
-	m.gRPC.Stop = make(chan struct{})
 	m.startTime = time.Now()
 
 	// for log service
@@ -112,6 +363,7 @@ func (m *Mediator) Serve() {
 func (m *Mediator) Shutdown() {
 	m.stopAll <- struct{}{}
 	p.Print("Initializing shut down, please wait.")
+	m.health.Shutdown()
 	close(m.gRPC.Stop)
 	time.Sleep(1 * time.Second)
 	p.Print(fmt.Sprintf("Mediator handled %d requests during %v",
@@ -119,123 +371,201 @@ func (m *Mediator) Shutdown() {
 	p.Print("Log Mediator shut down")
 }
 
-func (m *Mediator) getConnection(s string) (*grpc.ClientConn, error) {
-	m.mux.Lock()
-	defer m.mux.Unlock()
-	var conn *grpc.ClientConn
-	for k, v := range m.scribeResponsibility {
-		toCheck := strings.ToLower(string(s[0]))
-		if toCheck >= k {
-			return m.scribesCon[v], nil
+// getScribe returns the id of the scribe responsible for the filename,
+// the one with the first key not before the first character of the
+// filename. Filenames starting with other characters go to the scribe
+// of the last key. Callers must hold mux.
+func (m *Mediator) getScribe(s string) string {
+	pos := len(responsibilityChars) - 1
+	if s != "" {
+		if i := strings.IndexByte(responsibilityChars, strings.ToLower(s[:1])[0]); i >= 0 {
+			pos = i
 		}
-		conn = m.scribesCon[v]
-		break
 	}
-	return conn, nil
+	keys := make([]int, 0, len(m.scribeResponsibility))
+	for k := range m.scribeResponsibility {
+		keys = append(keys, strings.Index(responsibilityChars, k))
+	}
+	sort.Ints(keys)
+	for _, k := range keys {
+		if k >= pos {
+			return m.scribeResponsibility[responsibilityChars[k:k+1]]
+		}
+	}
+	return ""
 }
 
 func (m *Mediator) startPingingSubcribers() {
 	for range time.Tick(5 * time.Second) {
-		m.mux.Lock()
 		m.pingSubscribers()
-		m.mux.Unlock()
 	}
 }
 
+// check is the result of checking a scribe
+type check struct {
+	addr string
+	// conn is the connection created for the check, if any
+	conn   *grpc.ClientConn
+	status healthpb.HealthCheckResponse_ServingStatus
+	err    error
+}
+
+// pingSubscribers checks the health of every scribe without holding mux,
+// which is held only to copy the scribes and to apply the results.
 func (m *Mediator) pingSubscribers() {
-	if len(m.scribes) == 0 {
-		return
+	m.mux.Lock()
+	scribes := make(map[string]string, len(m.scribes))
+	conns := make(map[string]*grpc.ClientConn, len(m.scribesCon))
+	for id, addr := range m.scribes {
+		scribes[id] = addr
+		conns[id] = m.scribesCon[id]
 	}
-	for scribe, addr := range m.scribes {
-		ok := m.checkSubscriberConnection(scribe, addr)
-		if ok {
-			continue
-		}
-		m.checkSubscriberAlive(scribe, addr)
+	m.mux.Unlock()
 
+	checks := make(map[string]check, len(scribes))
+	for id, addr := range scribes {
+		checks[id] = checkSubscriber(addr, conns[id], m.dialOptions...)
+	}
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	for id, c := range checks {
+		m.applyCheck(id, c)
 	}
 	m.reCalculateScribeResponsibility()
+	m.reportHealth()
 }
 
-// load balancing
-func (m *Mediator) reCalculateScribeResponsibility() {
-	r := "abcdefghijklmnopqrstuvwxyz0123456789"
-	scribeNum := len(m.scribes)
-	if scribeNum == 0 {
-		return
+// checkSubscriber connects to the scribe, unless conn is already
+// a connection to it, and checks whether it serves requests.
+func checkSubscriber(addr string, conn *grpc.ClientConn, opts ...grpc.DialOption) check {
+	c := check{addr: addr}
+	if conn == nil {
+		c.conn, c.err = createConnection(addr, opts...)
+		if c.err != nil {
+			return c
+		}
+		conn = c.conn
 	}
-	rNum := len(r)
-
-	m.scribeResponsibility = make(map[string]string)
-	mid := (rNum - 1) / scribeNum
+	c.status, c.err = checkHealth(conn)
+	return c
+}
 
-	val := mid
-	for s := range m.scribes {
-		p.Print(s)
-		m.scribeResponsibility[string(r[val])] = s
-		val += mid + 1
+// applyCheck deregisters the scribe when it couldn't be checked and keeps
+// whether it is serving requests. A check of a scribe deregistered, or
+// registered again, meanwhile is dropped. Callers must hold mux.
+func (m *Mediator) applyCheck(id string, c check) {
+	if addr, ok := m.scribes[id]; !ok || addr != c.addr {
+		if c.conn != nil {
+			c.conn.Close()
+		}
+		return
+	}
+	if c.conn != nil {
+		m.scribesCon[id] = c.conn
+	}
+	if c.err != nil {
+		if _, ok := m.scribesCon[id]; ok {
+			p.Print(fmt.Sprintf("Health check of scribe %s at %s failed: %s", id, c.addr, status.Convert(c.err).Message()))
+		}
+		m.deregister(id, c.addr)
+		return
+	}
+	serving := c.status == healthpb.HealthCheckResponse_SERVING
+	if serving != m.serving[id] {
+		p.Print(fmt.Sprintf("Scribe %s at %s is %v", id, c.addr, c.status))
 	}
+	m.serving[id] = serving
 }
 
-func (m *Mediator) checkSubscriberConnection(key, val string) bool {
-	if _, ok := m.scribesCon[key]; !ok {
-		conn, err := createConnection(val)
-		if err != nil {
-			delete(m.scribes, key)
-			delete(m.scribesCon, key)
-			p.Print(fmt.Sprintf("Deregistering scribe %s at %s", key, val))
-			return true
+// reCalculateScribeResponsibility divides the first characters of the
+// filenames in consecutive ranges, one for every serving scribe in the
+// order of their ids, keyed by the last character of the range.
+func (m *Mediator) reCalculateScribeResponsibility() {
+	r := responsibilityChars
+	serving := make([]string, 0, len(m.scribes))
+	for s := range m.scribes {
+		// a scribe not serving, i.e. with its disk full, gets no files
+		if m.serving[s] {
+			serving = append(serving, s)
+		}
+	}
+	// the scribes keep their files as long as they are the same
+	sort.Strings(serving)
+	m.scribeResponsibility = make(map[string]string)
+	scribeNum := len(serving)
+	for i, s := range serving {
+		// the last scribe always ends at the last character
+		val := (i+1)*len(r)/scribeNum - 1
+		if val < 0 {
+			// more scribes than characters
+			continue
 		}
-		m.scribesCon[key] = conn
-		return true
+		m.scribeResponsibility[string(r[val])] = s
 	}
-	return false
 }
 
-func (m *Mediator) checkSubscriberAlive(key, val string) {
-	if !m.isSubscriberAlive(m.scribesCon[key]) {
-		delete(m.scribes, key)
-		delete(m.scribesCon, key)
-		p.Print(fmt.Sprintf("Deregistering scribe %s at %s", key, val))
+// deregister drops the scribe, closing its connection and its stream.
+// Callers must hold mux.
+func (m *Mediator) deregister(key, val string) {
+	if stream, ok := m.scribesStream[key]; ok {
+		// cancelling, unlike closing, is safe while the stream is sending
+		stream.cancel()
+		delete(m.scribesStream, key)
 	}
+	if conn, ok := m.scribesCon[key]; ok {
+		conn.Close()
+	}
+	delete(m.scribes, key)
+	delete(m.scribesCon, key)
+	delete(m.serving, key)
+	p.Print(fmt.Sprintf("Deregistering scribe %s at %s", key, val))
 }
 
-func (m *Mediator) isSubscriberAlive(conn *grpc.ClientConn) bool {
-	c := pb.NewPingerClient(conn)
-	req := &pb.PingRequest{
-		A: rand.Int31(),
-		B: rand.Int31(),
-	}
+// reportHealth sets the mediator as serving requests when there
+// are scribes to forward them to. Callers must hold mux.
+func (m *Mediator) reportHealth() {
+	m.health.SetServing(service.LogScribeService, len(m.scribeResponsibility) > 0)
+	m.health.SetServing(service.LogReaderService, len(m.scribesCon) > 0)
+}
 
-	r, err := c.Ping(context.Background(), req)
+// checkHealth asks the scribe whether it serves log requests
+func checkHealth(conn *grpc.ClientConn) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+	c := healthpb.NewHealthClient(conn)
+	r, err := c.Check(ctx, &healthpb.HealthCheckRequest{Service: service.LogScribeService})
 	if err != nil {
-		return false
-	}
-	if r.GetRes() != req.GetA()*req.GetB() {
-		return false
+		return healthpb.HealthCheckResponse_UNKNOWN, err
 	}
-	return true
+	return r.GetStatus(), nil
 }
 
-func createConnection(addr string) (*grpc.ClientConn, error) {
-	conn, err := grpc.Dial(addr,
+func createConnection(addr string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{
 		grpc.WithInsecure(),
-		grpc.WithTimeout(1*time.Second))
+		grpc.WithTimeout(1 * time.Second),
+	}, opts...)
+	conn, err := grpc.Dial(addr, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to scribe: %v", err)
 	}
 	return conn, nil
 }
 
+// Logger returns the service pushing requests to the mediator's stream
+func (m *Mediator) Logger() service.Logger {
+	return service.Logger{Stream: m.stream, Stop: m.gRPC.Stop}
+}
+
 func (m *Mediator) register() func() {
 	return func() {
-		l := &service.Logger{
-			Stream: m.stream,
-		}
-		pb.RegisterLogScribeServer(m.gRPC.Server, l)
+		pb.RegisterLogScribeServer(m.gRPC.Server, m.Logger())
+		pb.RegisterLogReaderServer(m.gRPC.Server, reader{m})
+		healthpb.RegisterHealthServer(m.gRPC.Server, m.health)
 
 		med := &service.Register{
-			Subscribers: m.scribes,
+			Subscribe: m.subscribe,
 		}
 		pb.RegisterRegisterServer(m.gRPC.Server, med)
 	}
@@ -1,11 +1,27 @@
 package mediator
 
-import "testing"
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	"github.com/RomanosTrechlis/go-scribe/service"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
 
 func Test_ReCalculateScribeResponsibility(t *testing.T) {
 	m := &Mediator{
 		scribes:              make(map[string]string),
 		scribeResponsibility: make(map[string]string),
+		serving:              make(map[string]bool),
 	}
 
 	t.Run("no scribes", func(t *testing.T) {
@@ -18,6 +34,7 @@ func Test_ReCalculateScribeResponsibility(t *testing.T) {
 
 	t.Run("single scribe", func(t *testing.T) {
 		m.scribes["1"] = "scribe:1"
+		m.serving["1"] = true
 		m.reCalculateScribeResponsibility()
 		if len(m.scribeResponsibility) != 1 {
 			t.Errorf("expected len of 1 and got %d", len(m.scribeResponsibility))
@@ -33,6 +50,7 @@ func Test_ReCalculateScribeResponsibility(t *testing.T) {
 	// two scribes
 	t.Run("two scribes", func(t *testing.T) {
 		m.scribes["2"] = "scribe:2"
+		m.serving["2"] = true
 		m.reCalculateScribeResponsibility()
 		if len(m.scribeResponsibility) != 2 {
 			t.Errorf("expected len of 2 and got %d", len(m.scribeResponsibility))
@@ -45,6 +63,16 @@ func Test_ReCalculateScribeResponsibility(t *testing.T) {
 		}
 	})
 
+	t.Run("scribe not serving", func(t *testing.T) {
+		m.serving["2"] = false
+		m.reCalculateScribeResponsibility()
+		if len(m.scribeResponsibility) != 1 {
+			t.Errorf("expected len of 1 and got %d", len(m.scribeResponsibility))
+		}
+		if m.scribeResponsibility["9"] != "1" {
+			t.Errorf("expected scribe 1 to be responsible and got %v", m.scribeResponsibility)
+		}
+	})
 }
 
 func TestNew(t *testing.T) {
@@ -53,14 +81,18 @@ func TestNew(t *testing.T) {
 		crt  string
 		pk   string
 		ca   string
+		opts []Option
 
 		err bool
 	}{
-		{1122, "", "", "", false},
-		{1122, "dummy", "dummy", "dummy", true},
+		{1122, "", "", "", nil, false},
+		{1122, "dummy", "dummy", "dummy", nil, true},
+		{1122, "", "", "", []Option{WithCompression("gzip")}, false},
+		{1122, "", "", "", []Option{WithCompression("none")}, false},
+		{1122, "", "", "", []Option{WithCompression("lz4")}, true},
 	}
 	for _, tt := range tests {
-		_, err := New(tt.port, tt.crt, tt.pk, tt.ca)
+		_, err := New(tt.port, tt.crt, tt.pk, tt.ca, tt.opts...)
 		if err != nil && !tt.err {
 			t.Errorf("expecting no err, got error %v", err)
 		}
@@ -69,3 +101,311 @@ func TestNew(t *testing.T) {
 		}
 	}
 }
+
+func TestGetScribe(t *testing.T) {
+	m := &Mediator{
+		scribes:              map[string]string{"c": "scribe:3", "a": "scribe:1", "b": "scribe:2"},
+		scribeResponsibility: make(map[string]string),
+		serving:              map[string]bool{"a": true, "b": true, "c": true},
+	}
+	for i := 0; i < 10; i++ {
+		m.reCalculateScribeResponsibility()
+		exp := map[string]string{"l": "a", "x": "b", "9": "c"}
+		if !reflect.DeepEqual(m.scribeResponsibility, exp) {
+			t.Fatalf("expected %v and got %v", exp, m.scribeResponsibility)
+		}
+	}
+
+	var tests = []struct {
+		filename string
+		exp      string
+	}{
+		{"app", "a"},
+		{"Log", "a"},
+		{"main", "b"},
+		{"x", "b"},
+		{"y", "c"},
+		{"2018", "c"},
+		{"_other", "c"},
+		{"", "c"},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 10; i++ {
+			if id := m.getScribe(tt.filename); id != tt.exp {
+				t.Fatalf("expected scribe %s for '%s' and got '%s'", tt.exp, tt.filename, id)
+			}
+		}
+	}
+
+	// every count of scribes gets the whole range
+	for n := 1; n <= 40; n++ {
+		m.scribes = make(map[string]string)
+		m.serving = make(map[string]bool)
+		for i := 0; i < n; i++ {
+			id := fmt.Sprintf("%02d", i)
+			m.scribes[id] = "scribe:" + id
+			m.serving[id] = true
+		}
+		m.reCalculateScribeResponsibility()
+		if m.scribeResponsibility["9"] == "" || m.getScribe("a") == "" {
+			t.Errorf("expected every filename to have a scribe with %d scribes and got %v", n, m.scribeResponsibility)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	m := &Mediator{
+		scribeResponsibility: map[string]string{"9": "1"},
+	}
+	batch := pb.LogBatch{Entries: []*pb.LogRequest{
+		{Filename: "a", Line: "1"},
+		{Filename: "z", Line: "2"},
+		{Filename: "a", Line: "3"},
+	}}
+
+	parts := m.split(batch)
+	if len(parts) != 1 {
+		t.Fatalf("expected 1 part and got %d", len(parts))
+	}
+	entries := parts["1"]
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries for scribe '1' and got %d", len(entries))
+	}
+	for i, e := range entries {
+		if e != batch.Entries[i] {
+			t.Errorf("expected entry %d to keep its order", i)
+		}
+	}
+}
+
+func TestForwardNoScribe(t *testing.T) {
+	m := &Mediator{
+		scribeResponsibility: map[string]string{"9": "1"},
+		scribesStream:        make(map[string]*scribeStream),
+	}
+	err := m.forward(service.Entry{Batch: pb.LogBatch{Entries: []*pb.LogRequest{{Filename: "a", Line: "1"}}}})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("expected code %v and got '%v'", codes.Unavailable, err)
+	}
+	// the lock is released for the health checks and the readers
+	m.mux.Lock()
+	m.mux.Unlock()
+}
+
+// serve starts a server of the services registered
+// on a local port and returns a connection to it
+func serve(t *testing.T, register func(srv *grpc.Server)) (*grpc.ClientConn, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	register(srv)
+	go srv.Serve(lis)
+	conn, err := createConnection(lis.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	return conn, func() {
+		conn.Close()
+		srv.Stop()
+	}
+}
+
+func TestForwardStream(t *testing.T) {
+	stream := make(chan service.Entry, 10)
+	l := service.Logger{Stream: stream, Stop: make(chan struct{}), Admit: func(b *pb.LogBatch) error {
+		if b.GetEntries()[0].GetFilename() == "full" {
+			return service.ResourceExhausted("", "disk space is low")
+		}
+		return nil
+	}}
+	conn, stop := serve(t, func(srv *grpc.Server) { pb.RegisterLogScribeServer(srv, l) })
+	defer stop()
+
+	m := &Mediator{
+		scribesCon:           map[string]*grpc.ClientConn{"1": conn},
+		scribeResponsibility: map[string]string{"9": "1"},
+		scribesStream:        make(map[string]*scribeStream),
+	}
+	defer m.closeStreams()
+	forward := func(filename string) error {
+		return m.forward(service.Entry{Batch: pb.LogBatch{Entries: []*pb.LogRequest{{Filename: filename, Line: "1"}}}})
+	}
+	for _, f := range []string{"a", "full"} {
+		if err := forward(f); err != nil {
+			t.Fatalf("expected nil error and got '%v'", err)
+		}
+	}
+	// the scribe ends the stream rejecting full
+	time.Sleep(50 * time.Millisecond)
+	if err := forward("b"); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	for _, exp := range []string{"a", "b"} {
+		select {
+		case e := <-stream:
+			if f := e.Batch.GetEntries()[0].GetFilename(); f != exp {
+				t.Errorf("expected a request for %s and got %s", exp, f)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected a request for %s", exp)
+		}
+	}
+}
+
+// fakeReader sends its results to every search
+type fakeReader struct {
+	pb.LogReaderServer
+	results []*pb.SearchResult
+}
+
+func (f fakeReader) Search(req *pb.SearchRequest, stream pb.LogReader_SearchServer) error {
+	for _, r := range f.results {
+		if err := stream.Send(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// searchStream collects the results sent by Search
+type searchStream struct {
+	grpc.ServerStream
+	results []*pb.SearchResult
+}
+
+func (s *searchStream) Send(r *pb.SearchResult) error {
+	s.results = append(s.results, r)
+	return nil
+}
+
+func (s *searchStream) Context() context.Context {
+	return context.Background()
+}
+
+func TestSearchOrder(t *testing.T) {
+	result := func(filename string, line int64) *pb.SearchResult {
+		return &pb.SearchResult{Filename: filename, Segment: filename + ".log", LineNumber: line}
+	}
+	m := &Mediator{scribesCon: make(map[string]*grpc.ClientConn)}
+	for id, results := range map[string][]*pb.SearchResult{
+		"1": {result("a", 1), result("a", 2), result("c", 1)},
+		"2": {result("a", 9), result("b", 1)},
+	} {
+		r := fakeReader{results: results}
+		conn, stop := serve(t, func(srv *grpc.Server) { pb.RegisterLogReaderServer(srv, r) })
+		defer stop()
+		m.scribesCon[id] = conn
+	}
+
+	var tc = []struct {
+		limit int32
+		exp   string
+	}{
+		{0, "1:a:1,1:a:2,2:a:9,2:b:1,1:c:1"},
+		{4, "1:a:1,1:a:2,2:a:9,2:b:1"},
+	}
+	for _, tt := range tc {
+		// merged by file, the results are the same every time
+		for i := 0; i < 5; i++ {
+			stream := &searchStream{}
+			if err := (reader{m}).Search(&pb.SearchRequest{Query: "x", Limit: tt.limit}, stream); err != nil {
+				t.Fatalf("expected nil error and got '%v'", err)
+			}
+			got := make([]string, 0)
+			for _, r := range stream.results {
+				got = append(got, fmt.Sprintf("%s:%s:%d", r.GetScribe(), r.GetFilename(), r.GetLineNumber()))
+			}
+			if strings.Join(got, ",") != tt.exp {
+				t.Fatalf("expected %s and got %s", tt.exp, strings.Join(got, ","))
+			}
+		}
+	}
+}
+
+// slowHealth answers the health checks once released
+type slowHealth struct {
+	checking chan struct{}
+	release  chan struct{}
+}
+
+func (h slowHealth) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	h.checking <- struct{}{}
+	<-h.release
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func (h slowHealth) Watch(req *healthpb.HealthCheckRequest, s healthpb.Health_WatchServer) error {
+	return status.Error(codes.Unimplemented, "")
+}
+
+func TestPingWithoutLock(t *testing.T) {
+	h := slowHealth{checking: make(chan struct{}), release: make(chan struct{})}
+	conn, stop := serve(t, func(srv *grpc.Server) { healthpb.RegisterHealthServer(srv, h) })
+	defer stop()
+	m, err := New(1122, "", "", "")
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	m.scribes["1"] = conn.Target()
+	m.scribesCon["1"] = conn
+	m.serving = make(map[string]bool)
+	// a scribe deregistered while being checked stays deregistered
+	m.scribes["2"] = conn.Target()
+
+	done := make(chan struct{})
+	go func() {
+		m.pingSubscribers()
+		close(done)
+	}()
+	<-h.checking
+	// the readers and the writes aren't blocked by the health checks
+	m.connections()
+	m.mux.Lock()
+	delete(m.scribes, "2")
+	m.mux.Unlock()
+	close(h.release)
+	<-h.checking
+	<-done
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if !m.serving["1"] || m.scribeResponsibility["9"] != "1" {
+		t.Errorf("expected scribe 1 to be serving every file and got %v", m.scribeResponsibility)
+	}
+	if _, ok := m.scribesCon["2"]; ok {
+		t.Errorf("expected no connection to the deregistered scribe 2")
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	m, err := New(1122, "", "", "")
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	r := &service.Register{Subscribe: m.subscribe}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			r.Register(context.Background(), &pb.RegisterRequest{Id: fmt.Sprint(i), Addr: "scribe:1"})
+		}
+	}()
+	// the info is read while the scribes register
+	for len(m.GetInfo().Scribes) < 50 {
+		time.Sleep(time.Millisecond)
+	}
+	<-done
+
+	r.Register(context.Background(), &pb.RegisterRequest{Id: "1", Addr: "scribe:2"})
+	info := m.GetInfo()
+	if len(info.Scribes) != 50 || info.Scribes["1"] != "scribe:2" {
+		t.Errorf("expected 50 scribes with 1 at scribe:2 and got %v", info.Scribes)
+	}
+	// the info is a copy
+	info.Scribes["x"] = "scribe:3"
+	if _, ok := m.GetInfo().Scribes["x"]; ok {
+		t.Errorf("expected the info not to change the scribes")
+	}
+}
@@ -0,0 +1,216 @@
+package mediator
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	p "github.com/RomanosTrechlis/go-scribe/internal/util/format/print"
+	"github.com/RomanosTrechlis/go-scribe/service"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// reader implements the LogReader protobuf service
+// by proxying the requests to the scribes.
+type reader struct {
+	m *Mediator
+}
+
+// Tail implements the Tail protobuf service by following
+// the file on the scribe responsible for it.
+func (r reader) Tail(req *pb.TailRequest, stream pb.LogReader_TailServer) error {
+	if _, err := service.ValidateFile("", req.GetPath(), req.GetFilename()); err != nil {
+		return err
+	}
+
+	id, conn := r.m.responsible(req.GetFilename())
+	if conn == nil {
+		return service.Unavailable("no scribe available for file "+req.GetFilename(), 5*time.Second)
+	}
+
+	client := pb.NewLogReaderClient(conn)
+	tail, err := client.Tail(stream.Context(), req)
+	if err != nil {
+		return forwardError(id, err)
+	}
+	for {
+		l, err := tail.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return forwardError(id, err)
+		}
+		if err := stream.Send(l); err != nil {
+			return err
+		}
+	}
+}
+
+// Read implements the Read protobuf service by reading
+// the file from the scribe responsible for it.
+func (r reader) Read(ctx context.Context, req *pb.ReadRequest) (*pb.ReadResponse, error) {
+	if _, err := service.ValidateFile("", req.GetPath(), req.GetFilename()); err != nil {
+		return nil, err
+	}
+
+	id, conn := r.m.responsible(req.GetFilename())
+	if conn == nil {
+		return nil, service.Unavailable("no scribe available for file "+req.GetFilename(), 5*time.Second)
+	}
+
+	client := pb.NewLogReaderClient(conn)
+	resp, err := client.Read(ctx, req)
+	if err != nil {
+		return nil, forwardError(id, err)
+	}
+	return resp, nil
+}
+
+// defaultSearchLimit is the number of results
+// returned by Search when no limit is given.
+const defaultSearchLimit = 1000
+
+// Search implements the Search protobuf service by sending the query
+// to every scribe and streaming back their results merged in the order
+// of their files, the results of a file keeping the order of its scribe.
+// The search fails only when it fails on every scribe.
+func (r reader) Search(req *pb.SearchRequest, stream pb.LogReader_SearchServer) error {
+	if req.GetQuery() == "" {
+		return service.InvalidArgument("query", "must not be empty")
+	}
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	conns := r.m.connections()
+	if len(conns) == 0 {
+		return service.Unavailable("no scribe available", 5*time.Second)
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	ids := make([]string, 0, len(conns))
+	for id := range conns {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	results := make([]chan *pb.SearchResult, len(ids))
+	errs := make(chan error, len(conns))
+	for i, id := range ids {
+		results[i] = make(chan *pb.SearchResult)
+		go func(id string, conn *grpc.ClientConn, results chan *pb.SearchResult) {
+			defer close(results)
+			errs <- search(ctx, id, conn, req, results)
+		}(id, conns[id], results[i])
+	}
+
+	// every scribe sends its results ordered by file, segment and line,
+	// so merging them by file keeps that order across the scribes
+	heads := make([]*pb.SearchResult, len(ids))
+	for i := range results {
+		heads[i] = <-results[i]
+	}
+	sent := 0
+	for sent < limit {
+		next := -1
+		for i, h := range heads {
+			if h != nil && (next < 0 || before(h, heads[next])) {
+				next = i
+			}
+		}
+		if next < 0 {
+			break
+		}
+		if err := stream.Send(heads[next]); err != nil {
+			return err
+		}
+		sent++
+		heads[next] = <-results[next]
+	}
+	// the scribes stop searching and the results already on their way are dropped
+	cancel()
+	for i := range results {
+		for range results[i] {
+		}
+	}
+	close(errs)
+
+	var first error
+	failed := 0
+	for err := range errs {
+		if err == nil {
+			continue
+		}
+		if first == nil {
+			first = err
+		}
+		failed++
+		p.Print(fmt.Sprintf("search failed: %v", err))
+	}
+	if failed == len(conns) && sent == 0 {
+		return first
+	}
+	return nil
+}
+
+// before checks if the result a belongs to a file before the one of b
+func before(a, b *pb.SearchResult) bool {
+	if a.GetPath() != b.GetPath() {
+		return a.GetPath() < b.GetPath()
+	}
+	return a.GetFilename() < b.GetFilename()
+}
+
+// search runs the query on a scribe and sends the results to the channel
+func search(ctx context.Context, id string, conn *grpc.ClientConn, req *pb.SearchRequest, results chan *pb.SearchResult) error {
+	client := pb.NewLogReaderClient(conn)
+	s, err := client.Search(ctx, req)
+	if err != nil {
+		return forwardError(id, err)
+	}
+	for {
+		res, err := s.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return forwardError(id, err)
+		}
+		if res.GetScribe() == "" {
+			res.Scribe = id
+		}
+		select {
+		case results <- res:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// connections returns the connections to the scribes by their id
+func (m *Mediator) connections() map[string]*grpc.ClientConn {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	conns := make(map[string]*grpc.ClientConn, len(m.scribesCon))
+	for id, conn := range m.scribesCon {
+		conns[id] = conn
+	}
+	return conns
+}
+
+// responsible returns the id and the connection
+// of the scribe responsible for the filename.
+func (m *Mediator) responsible(filename string) (string, *grpc.ClientConn) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	id := m.getScribe(filename)
+	return id, m.scribesCon[id]
+}
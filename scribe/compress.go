@@ -0,0 +1,189 @@
+package scribe
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	p "github.com/RomanosTrechlis/go-scribe/internal/util/format/print"
+)
+
+// Compressions of the rotated segments
+const (
+	// CompressNone keeps the rotated segments as they are
+	CompressNone = "none"
+	// CompressGzip compresses the rotated segments to filename_<timestamp>.log.gz
+	CompressGzip = "gzip"
+)
+
+// gzipExt is appended to the names of the compressed segments
+const gzipExt = ".gz"
+
+// compressor compresses the rotated segments in the background, so that
+// writes never wait for it. A segment is replaced by its compressed copy
+// only after the copy is complete, so readers find every segment either
+// compressed or not. The segments left uncompressed when the scribe
+// stopped are compressed when it starts again.
+type compressor struct {
+	rootPath string
+	// retention, when not nil, is held while replacing a segment,
+	// so that the retention doesn't delete it meanwhile
+	retention sync.Locker
+
+	mu      sync.Mutex
+	pending []string
+	// wake tells the worker that segments are pending
+	wake chan struct{}
+}
+
+// newCompressor returns the compressor of the codec, gzip or none,
+// which is nil when the segments aren't compressed.
+func newCompressor(codec, rootPath string) (*compressor, error) {
+	switch codec {
+	case "", CompressNone:
+		return nil, nil
+	case CompressGzip:
+		return &compressor{rootPath: rootPath, wake: make(chan struct{}, 1)}, nil
+	default:
+		return nil, fmt.Errorf("unknown compression '%s', gzip or none", codec)
+	}
+}
+
+// enqueue schedules the rotated segment for compression without waiting
+func (c *compressor) enqueue(file string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.pending = append(c.pending, file)
+	c.mu.Unlock()
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// start compresses the segments left uncompressed under the root path
+// and then the segments enqueued, until stop is closed.
+func (c *compressor) start(stop chan struct{}) {
+	if c == nil {
+		return
+	}
+	if err := c.scan(); err != nil {
+		p.Print(fmt.Sprintf("failed to find segments to compress: %v", err))
+	}
+	for {
+		select {
+		case <-c.wake:
+		case <-stop:
+			return
+		}
+		for {
+			c.mu.Lock()
+			if len(c.pending) == 0 {
+				c.mu.Unlock()
+				break
+			}
+			file := c.pending[0]
+			c.pending = c.pending[1:]
+			c.mu.Unlock()
+
+			if err := compressFile(file, c.retention); err != nil {
+				p.Print(fmt.Sprintf("failed to compress segment: %v", err))
+			}
+			select {
+			case <-stop:
+				return
+			default:
+			}
+		}
+	}
+}
+
+// scan enqueues the rotated segments that aren't compressed
+func (c *compressor) scan() error {
+	files, err := logicalFiles(c.rootPath, "")
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		segs, err := segments(c.rootPath, f.path, f.filename)
+		if err != nil {
+			return err
+		}
+		for _, s := range segs {
+			name := filepath.Base(s.file)
+			if name != f.filename+".log" && !strings.HasSuffix(name, gzipExt) {
+				c.enqueue(s.file)
+			}
+		}
+	}
+	return nil
+}
+
+// compressFile replaces the file with its gzip compressed copy, file.gz,
+// which keeps the modification time of the file, holding retention, when
+// not nil, while replacing it. A file that doesn't exist anymore, i.e.
+// deleted by the retention, even while being compressed, is skipped.
+func compressFile(file string, retention sync.Locker) error {
+	in, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't open segment '%s': %v", file, err)
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return fmt.Errorf("couldn't stat segment '%s': %v", file, err)
+	}
+
+	// the copy gets its name only when complete
+	tmp := file + gzipExt + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("couldn't create '%s': %v", tmp, err)
+	}
+	err = func() error {
+		defer out.Close()
+		gz := gzip.NewWriter(out)
+		if _, err := io.Copy(gz, in); err != nil {
+			return err
+		}
+		if err := gz.Close(); err != nil {
+			return err
+		}
+		return out.Sync()
+	}()
+	if err == nil {
+		err = os.Chtimes(tmp, info.ModTime(), info.ModTime())
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("couldn't compress segment '%s': %v", file, err)
+	}
+	in.Close()
+
+	if retention != nil {
+		retention.Lock()
+		defer retention.Unlock()
+	}
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		// the retention deleted it, so its copy mustn't bring it back
+		os.Remove(tmp)
+		return nil
+	}
+	if err := os.Rename(tmp, file+gzipExt); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("couldn't compress segment '%s': %v", file, err)
+	}
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("couldn't delete compressed segment '%s': %v", file, err)
+	}
+	return nil
+}
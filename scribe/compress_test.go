@@ -0,0 +1,158 @@
+package scribe
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+)
+
+func TestNewCompressor(t *testing.T) {
+	var tc = []struct {
+		codec   string
+		enabled bool
+		err     bool
+	}{
+		{"", false, false},
+		{CompressNone, false, false},
+		{CompressGzip, true, false},
+		{"zstd", false, true},
+	}
+	for _, tt := range tc {
+		c, err := newCompressor(tt.codec, "")
+		if tt.err != (err != nil) {
+			t.Errorf("expected error %v for '%s' and got '%v'", tt.err, tt.codec, err)
+		}
+		if tt.enabled != (c != nil) {
+			t.Errorf("expected compressor %v for '%s'", tt.enabled, tt.codec)
+		}
+	}
+}
+
+func TestCompressor(t *testing.T) {
+	root, err := ioutil.TempDir("", "compress")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	// a segment rotated before the compressor started
+	old := filepath.Join(root, "c_01092018100000.log")
+	if err := ioutil.WriteFile(old, []byte("0\n"), 0644); err != nil {
+		t.Fatalf("failed to create %s: %v", old, err)
+	}
+	c, _ := newCompressor(CompressGzip, root)
+	stop := make(chan struct{})
+	defer close(stop)
+	go c.start(stop)
+
+	files := newFileCache(1, 0)
+	defer files.closeAll()
+	files.compressor = c
+	// the second and third writes rotate the file
+	for _, line := range []string{"1", "2", "3"} {
+		if err := files.write(root, "", "c", []string{line}, 1, pb.Ack_ACK_RECEIVED, nil); err != nil {
+			t.Fatalf("expected nil error and got '%v'", err)
+		}
+	}
+
+	compressed := func() int {
+		segs, _ := segments(root, "", "c")
+		n := 0
+		for _, s := range segs {
+			if strings.HasSuffix(s.file, gzipExt) {
+				n++
+			}
+		}
+		return n
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for compressed() != 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := compressed(); n != 3 {
+		t.Fatalf("expected 3 compressed segments and got %d", n)
+	}
+
+	// the compressed segments are read like the rest
+	files.flushAll()
+	segs, err := segments(root, "", "c")
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	lines := make([]string, 0)
+	for _, s := range segs {
+		err := s.readLines(func(line string) bool {
+			lines = append(lines, line)
+			return true
+		})
+		if err != nil {
+			t.Fatalf("expected nil error and got '%v'", err)
+		}
+	}
+	if strings.Join(lines, ",") != "0,1,2,3" {
+		t.Errorf("expected lines 0,1,2,3 and got %v", lines)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be replaced by its compressed copy", old)
+	}
+
+	list, err := logicalFiles(root, "")
+	if err != nil || len(list) != 1 || list[0].filename != "c" {
+		t.Errorf("expected the logical file c and got %v, '%v'", list, err)
+	}
+}
+
+func TestSegmentsBeingCompressed(t *testing.T) {
+	root := createSegments(t)
+	defer os.RemoveAll(root)
+
+	// app_02092018100000.log is compressed, while
+	// app_01092018100000.log is still being compressed
+	if err := compressFile(filepath.Join(root, "app_02092018100000.log"), nil); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	ioutil.WriteFile(filepath.Join(root, "app_01092018100000.log.gz"), nil, 0644)
+
+	segs, err := segments(root, "", "app")
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	exp := []string{"app_01092018100000.log", "app_02092018100000.log.gz", "app.log"}
+	if len(segs) != len(exp) {
+		t.Fatalf("expected %d segments and got %d", len(exp), len(segs))
+	}
+	for i, s := range segs {
+		if filepath.Base(s.file) != exp[i] {
+			t.Errorf("expected segment %d to be %s and got %s", i, exp[i], filepath.Base(s.file))
+		}
+	}
+}
+
+// deletingLock deletes the file when locked, as the retention
+// would while the file is being compressed
+type deletingLock struct {
+	file string
+}
+
+func (l deletingLock) Lock()   { os.Remove(l.file) }
+func (l deletingLock) Unlock() {}
+
+func TestCompressDeleted(t *testing.T) {
+	root := createSegments(t)
+	defer os.RemoveAll(root)
+
+	file := filepath.Join(root, "app_02092018100000.log")
+	if err := compressFile(file, deletingLock{file}); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	for _, f := range []string{file, file + gzipExt, file + gzipExt + ".tmp"} {
+		if _, err := os.Stat(f); !os.IsNotExist(err) {
+			t.Errorf("expected %s not to exist", f)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package scribe
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	p "github.com/RomanosTrechlis/go-scribe/internal/util/format/print"
+	"github.com/RomanosTrechlis/go-scribe/service"
+)
+
+// defaultDiskInterval is the time between checks of the free space
+const defaultDiskInterval = 5 * time.Second
+
+// KeepNone is the Keep severity of the watermarks rejecting every request
+const KeepNone = pb.Severity_FATAL + 1
+
+// Watermarks define when the scribe degrades because its disk is running out.
+// The scribe degrades when the free space of the log root drops below Low and
+// recovers when it rises to High again. While degraded, requests with
+// severity lower than Keep are dropped from their batches, a batch being
+// rejected with ResourceExhausted when none of it is left or when it asks
+// to be acknowledged once written, and the scribe reports it is not serving,
+// so that a mediator sends its files to other scribes. Zero Low disables
+// the watermarks.
+type Watermarks struct {
+	// Low and High are free bytes, High being at least Low
+	Low  int64
+	High int64
+	// Keep is the lowest severity still written while degraded.
+	// UNSPECIFIED keeps every request and KeepNone none.
+	Keep pb.Severity
+	// Interval is the time between checks, five seconds when zero
+	Interval time.Duration
+}
+
+// DiskStats describes the free space of the log root
+type DiskStats struct {
+	Free     int64
+	Total    int64
+	Degraded bool
+	// Shed is the number of requests dropped, or rejected, while degraded
+	Shed int64
+}
+
+// diskMonitor checks the free space of the log root against the watermarks
+type diskMonitor struct {
+	rootPath string
+	marks    Watermarks
+	// onChange is called when the monitor degrades or recovers
+	onChange func(degraded bool)
+
+	mu       sync.Mutex
+	free     int64
+	total    int64
+	degraded bool
+	shed     int64
+}
+
+func (d *diskMonitor) enabled() bool {
+	return d.marks.Low > 0
+}
+
+// start checks the free space every interval until stop is closed
+func (d *diskMonitor) start(stop chan struct{}) {
+	if !d.enabled() {
+		return
+	}
+	interval := d.marks.Interval
+	if interval <= 0 {
+		interval = defaultDiskInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := d.check(); err != nil {
+			p.Print(fmt.Sprintf("failed to check free space: %v", err))
+		}
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// check updates the free space and degrades, or recovers,
+// the scribe when it crosses the watermarks.
+func (d *diskMonitor) check() error {
+	free, total, err := freeSpace(d.rootPath)
+	if err != nil {
+		return err
+	}
+	d.update(int64(free), int64(total))
+	return nil
+}
+
+func (d *diskMonitor) update(free, total int64) {
+	d.mu.Lock()
+	d.free, d.total = free, total
+	changed := false
+	switch {
+	case !d.degraded && free < d.marks.Low:
+		d.degraded, changed = true, true
+		p.Print(fmt.Sprintf("Free space of %s is %d bytes, below %d, rejecting requests below %s",
+			d.rootPath, free, d.marks.Low, keepName(d.marks.Keep)))
+	case d.degraded && free >= d.marks.High:
+		d.degraded, changed = false, true
+		p.Print(fmt.Sprintf("Free space of %s is %d bytes, accepting every request again", d.rootPath, free))
+	}
+	degraded := d.degraded
+	d.mu.Unlock()
+	if changed && d.onChange != nil {
+		d.onChange(degraded)
+	}
+}
+
+// isDegraded checks if the free space is below the watermarks
+func (d *diskMonitor) isDegraded() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.degraded
+}
+
+// admit drops, while degraded, the requests of the batch with severity
+// lower than the one kept. The batch is rejected when none is left, or when
+// the client waits for the write of every request of it.
+func (d *diskMonitor) admit(b *pb.LogBatch) error {
+	if !d.isDegraded() {
+		return nil
+	}
+	entries := b.GetEntries()
+	kept := make([]*pb.LogRequest, 0, len(entries))
+	for _, e := range entries {
+		if e.GetSeverity() >= d.marks.Keep {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) == len(entries) {
+		return nil
+	}
+	if len(kept) == 0 || b.GetAck() >= pb.Ack_ACK_WRITTEN {
+		atomic.AddInt64(&d.shed, int64(len(entries)))
+		return service.ResourceExhausted(d.rootPath,
+			fmt.Sprintf("disk space is low, requests below %s are rejected", keepName(d.marks.Keep)))
+	}
+	atomic.AddInt64(&d.shed, int64(len(entries)-len(kept)))
+	b.Entries = kept
+	return nil
+}
+
+// stats describes the free space as of the latest check
+func (d *diskMonitor) stats() DiskStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return DiskStats{
+		Free:     d.free,
+		Total:    d.total,
+		Degraded: d.degraded,
+		Shed:     atomic.LoadInt64(&d.shed),
+	}
+}
@@ -0,0 +1,128 @@
+package scribe
+
+import (
+	"testing"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	"github.com/RomanosTrechlis/go-scribe/service"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestDiskMonitor(t *testing.T) {
+	changes := make([]bool, 0)
+	d := &diskMonitor{
+		rootPath: "logs",
+		marks:    Watermarks{Low: 100, High: 200, Keep: pb.Severity_WARNING},
+		onChange: func(degraded bool) { changes = append(changes, degraded) },
+	}
+	info := func() *pb.LogBatch {
+		return &pb.LogBatch{Entries: []*pb.LogRequest{{Filename: "a", Severity: pb.Severity_INFO}}}
+	}
+	mixed := func(ack pb.Ack) *pb.LogBatch {
+		return &pb.LogBatch{Ack: ack, Entries: []*pb.LogRequest{
+			{Filename: "a", Severity: pb.Severity_ERROR},
+			{Filename: "a"},
+		}}
+	}
+	errors := func() *pb.LogBatch {
+		return &pb.LogBatch{Entries: []*pb.LogRequest{
+			{Filename: "a", Severity: pb.Severity_ERROR},
+			{Filename: "a", Severity: pb.Severity_WARNING},
+		}}
+	}
+
+	var tc = []struct {
+		name     string
+		free     int64
+		degraded bool
+		// the requests admitted of every batch, -1 when rejected
+		info, mixed, written, errors int
+	}{
+		{"plenty", 1000, false, 1, 2, 2, 2},
+		{"at low", 100, false, 1, 2, 2, 2},
+		{"below low", 99, true, -1, 1, -1, 2},
+		{"between", 150, true, -1, 1, -1, 2},
+		{"at high", 200, false, 1, 2, 2, 2},
+		{"between again", 150, false, 1, 2, 2, 2},
+	}
+	for _, tt := range tc {
+		d.update(tt.free, 1000)
+		if d.isDegraded() != tt.degraded {
+			t.Errorf("%s: expected degraded %v", tt.name, tt.degraded)
+		}
+		for _, b := range []struct {
+			batch *pb.LogBatch
+			exp   int
+		}{{info(), tt.info}, {mixed(pb.Ack_ACK_RECEIVED), tt.mixed},
+			{mixed(pb.Ack_ACK_WRITTEN), tt.written}, {errors(), tt.errors}} {
+			err := d.admit(b.batch)
+			if b.exp < 0 {
+				if status.Code(err) != codes.ResourceExhausted {
+					t.Errorf("%s: expected code %v and got '%v'", tt.name, codes.ResourceExhausted, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("%s: expected batch to be admitted and got '%v'", tt.name, err)
+			}
+			if len(b.batch.GetEntries()) != b.exp {
+				t.Errorf("%s: expected %d requests admitted and got %d", tt.name, b.exp, len(b.batch.GetEntries()))
+			}
+			if tt.degraded && b.batch.GetEntries()[0].GetSeverity() != pb.Severity_ERROR {
+				t.Errorf("%s: expected the request kept first", tt.name)
+			}
+		}
+	}
+
+	if len(changes) != 2 || !changes[0] || changes[1] {
+		t.Errorf("expected to degrade and recover once and got %v", changes)
+	}
+	st := d.stats()
+	// the rejected batches are shed whole
+	if st.Shed != 8 || st.Free != 150 || st.Degraded {
+		t.Errorf("expected 8 shed requests and 150 free bytes and got %+v", st)
+	}
+}
+
+func TestDiskHealth(t *testing.T) {
+	s := &LogScribe{health: service.NewHealth(service.LogScribeService)}
+	s.disk = &diskMonitor{marks: Watermarks{Low: 100, High: 100}, onChange: s.diskChanged}
+	check := func(exp healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		r, err := s.health.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service.LogScribeService})
+		if err != nil || r.GetStatus() != exp {
+			t.Errorf("expected %v and got %v, '%v'", exp, r.GetStatus(), err)
+		}
+	}
+
+	s.disk.update(50, 1000)
+	check(healthpb.HealthCheckResponse_NOT_SERVING)
+	// writes of the requests kept don't make the scribe serving
+	s.reportHealth(nil)
+	check(healthpb.HealthCheckResponse_NOT_SERVING)
+	s.disk.update(500, 1000)
+	check(healthpb.HealthCheckResponse_SERVING)
+
+	// a full disk recovers once space is reclaimed
+	s.reportHealth(service.ResourceExhausted("a.log", "no space left on device"))
+	check(healthpb.HealthCheckResponse_NOT_SERVING)
+	s.disk.update(50, 1000)
+	s.disk.update(500, 1000)
+	check(healthpb.HealthCheckResponse_SERVING)
+}
+
+func TestFreeSpace(t *testing.T) {
+	free, total, err := freeSpace(".")
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	if total == 0 || free > total {
+		t.Errorf("expected free space up to the total and got %d of %d", free, total)
+	}
+	if _, _, err := freeSpace("no_dir/at/all"); err == nil {
+		t.Errorf("expected error for missing directory")
+	}
+}
@@ -0,0 +1,179 @@
+package scribe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	p "github.com/RomanosTrechlis/go-scribe/internal/util/format/print"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Error policies of the failed writes
+const (
+	// OnErrorSkip drops the requests failed to be written
+	OnErrorSkip = "skip"
+	// OnErrorRetry retries the failed writes with backoff
+	// and drops the requests still failing
+	OnErrorRetry = "retry"
+	// OnErrorDeadLetter retries the failed writes with backoff and writes
+	// the requests still failing to the dead-letter file
+	OnErrorDeadLetter = "deadletter"
+)
+
+// DeadLetterPath is the directory, under the log root, of the dead-letter
+// file. Starting with '.' it can't be the path of a request, and it is left
+// out of the files listed, searched and deleted by the retention.
+const DeadLetterPath = ".deadletter"
+
+// DeadLetterFile is the file of the requests failed to be written
+const DeadLetterFile = "deadletter"
+
+const (
+	defaultRetries = 3
+	defaultBackoff = 100 * time.Millisecond
+	// maxBackoff is the longest wait between retries
+	maxBackoff = 10 * time.Second
+)
+
+// errStopped is returned by the retries when the scribe stops
+var errStopped = errors.New("scribe is stopping")
+
+// ErrorPolicy defines what happens to the requests failed to be written.
+// The requests asking to be acknowledged once written, or synced, are
+// retried but never dropped or dead-lettered, as their clients get the
+// error. Invalid requests are never retried.
+type ErrorPolicy struct {
+	// OnError is skip, retry or deadletter, skip when empty
+	OnError string
+	// Retries is the number of retries of a failed write, three when zero
+	Retries int
+	// Backoff is the wait before the first retry, doubling with every
+	// retry up to ten seconds, a hundred milliseconds when zero
+	Backoff time.Duration
+}
+
+// ErrorStats counts the failed writes
+type ErrorStats struct {
+	// Failed is the number of requests failed to be written, even after retrying
+	Failed int64
+	// Retried is the number of failed writes retried
+	Retried int64
+	// Skipped is the number of failed requests dropped
+	Skipped int64
+	// DeadLettered is the number of failed requests written to the dead-letter file
+	DeadLettered int64
+}
+
+func (e ErrorPolicy) validate() (ErrorPolicy, error) {
+	switch e.OnError {
+	case "":
+		e.OnError = OnErrorSkip
+	case OnErrorSkip, OnErrorRetry, OnErrorDeadLetter:
+	default:
+		return e, fmt.Errorf("unknown error policy '%s', skip, retry or deadletter", e.OnError)
+	}
+	if e.Retries < 0 || e.Backoff < 0 {
+		return e, fmt.Errorf("invalid error policy %+v", e)
+	}
+	if e.Retries == 0 {
+		e.Retries = defaultRetries
+	}
+	if e.Backoff == 0 {
+		e.Backoff = defaultBackoff
+	}
+	return e, nil
+}
+
+// retry calls write until it succeeds or the retries of the policy run out,
+// waiting between the calls, and returns the last error. It returns
+// errStopped when stop gets closed while waiting.
+func (s *LogScribe) retry(write func() error, stop chan struct{}) error {
+	err := write()
+	if err == nil || s.onError.OnError == OnErrorSkip || status.Code(err) == codes.InvalidArgument {
+		return err
+	}
+	backoff := s.onError.Backoff
+	for i := 0; i < s.onError.Retries && err != nil; i++ {
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-stop:
+			timer.Stop()
+			return errStopped
+		}
+		atomic.AddInt64(&s.errStats.Retried, 1)
+		err = write()
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+	return err
+}
+
+// discard drops the requests failed to be written,
+// or writes them to the dead-letter file, as the policy defines.
+func (s *LogScribe) discard(entries []*pb.LogRequest, failure error) {
+	n := int64(len(entries))
+	if s.onError.OnError != OnErrorDeadLetter {
+		atomic.AddInt64(&s.errStats.Skipped, n)
+		p.Print(fmt.Sprintf("skipping %d requests: %v", n, failure))
+		return
+	}
+	if err := s.deadLetter(entries, failure); err != nil {
+		atomic.AddInt64(&s.errStats.Skipped, n)
+		p.Print(fmt.Sprintf("skipping %d requests failed to be dead-lettered: %v, %v", n, failure, err))
+		return
+	}
+	atomic.AddInt64(&s.errStats.DeadLettered, n)
+}
+
+// deadLetter is a line of the dead-letter file
+type deadLetter struct {
+	Time     string `json:"time"`
+	Error    string `json:"error"`
+	Path     string `json:"path,omitempty"`
+	Filename string `json:"filename"`
+	// Line is the line as it would have been written to its file
+	Line string `json:"line"`
+}
+
+// deadLetter writes the requests to the dead-letter file,
+// every one as a JSON object along with the failure.
+func (s *LogScribe) deadLetter(entries []*pb.LogRequest, failure error) error {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		b, err := json.Marshal(deadLetter{
+			Time:     now,
+			Error:    failure.Error(),
+			Path:     e.GetPath(),
+			Filename: e.GetFilename(),
+			Line:     strings.TrimSuffix(s.formatter.Format(e), "\n"),
+		})
+		if err != nil {
+			return err
+		}
+		lines = append(lines, string(b))
+	}
+	key := filepath.Join(DeadLetterPath, DeadLetterFile)
+	return s.tails.write(key, lines, func() error {
+		return s.files.write(s.rootPath, DeadLetterPath, DeadLetterFile, lines, s.fileSize, pb.Ack_ACK_WRITTEN, nil)
+	})
+}
+
+// Errors counts the failed writes
+func (s *LogScribe) Errors() ErrorStats {
+	return ErrorStats{
+		Failed:       atomic.LoadInt64(&s.errStats.Failed),
+		Retried:      atomic.LoadInt64(&s.errStats.Retried),
+		Skipped:      atomic.LoadInt64(&s.errStats.Skipped),
+		DeadLettered: atomic.LoadInt64(&s.errStats.DeadLettered),
+	}
+}
@@ -0,0 +1,159 @@
+package scribe
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	"github.com/RomanosTrechlis/go-scribe/service"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorPolicy(t *testing.T) {
+	var tc = []struct {
+		policy ErrorPolicy
+		exp    ErrorPolicy
+		err    bool
+	}{
+		{ErrorPolicy{}, ErrorPolicy{OnErrorSkip, defaultRetries, defaultBackoff}, false},
+		{ErrorPolicy{OnErrorRetry, 5, time.Second}, ErrorPolicy{OnErrorRetry, 5, time.Second}, false},
+		{ErrorPolicy{OnError: OnErrorDeadLetter}, ErrorPolicy{OnErrorDeadLetter, defaultRetries, defaultBackoff}, false},
+		{ErrorPolicy{OnError: "panic"}, ErrorPolicy{}, true},
+		{ErrorPolicy{OnErrorRetry, -1, 0}, ErrorPolicy{}, true},
+	}
+	for _, tt := range tc {
+		e, err := tt.policy.validate()
+		if tt.err != (err != nil) {
+			t.Errorf("expected error %v for %+v and got '%v'", tt.err, tt.policy, err)
+			continue
+		}
+		if !tt.err && e != tt.exp {
+			t.Errorf("expected %+v and got %+v", tt.exp, e)
+		}
+	}
+}
+
+// failingScribe serves a scribe whose file blocked can't be written,
+// as a directory stands in its place
+func failingScribe(t *testing.T, policy ErrorPolicy) (*LogScribe, string, chan struct{}) {
+	root, err := ioutil.TempDir("", "failure")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(root, "blocked.log"), os.ModePerm); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	s, err := New("test", root, 0, -1, "", "", "", "", WithErrorPolicy(policy))
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	stop := make(chan struct{})
+	go s.serviceHandler(stop)
+	return s, root, stop
+}
+
+func TestWriteFailures(t *testing.T) {
+	s, root, stop := failingScribe(t, ErrorPolicy{OnError: OnErrorDeadLetter, Retries: 2, Backoff: time.Millisecond})
+	defer os.RemoveAll(root)
+	defer close(stop)
+	l := s.Logger()
+
+	// the client waiting for the write gets the error
+	_, err := l.Log(context.Background(), &pb.LogRequest{Filename: "blocked", Line: "0", Ack: pb.Ack_ACK_WRITTEN})
+	if err == nil {
+		t.Fatalf("expected error writing to blocked")
+	}
+
+	// the failure of a file doesn't stop the others or the next batches
+	_, err = l.LogBatch(context.Background(), &pb.LogBatch{Entries: []*pb.LogRequest{
+		{Filename: "ok", Line: "1"},
+		{Filename: "blocked", Line: "2"},
+	}})
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	_, err = l.Log(context.Background(), &pb.LogRequest{Filename: "ok", Line: "3", Ack: pb.Ack_ACK_WRITTEN})
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for s.Errors().DeadLettered != 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	exp := ErrorStats{Failed: 2, Retried: 4, Skipped: 0, DeadLettered: 1}
+	if errs := s.Errors(); errs != exp {
+		t.Errorf("expected %+v and got %+v", exp, errs)
+	}
+
+	s.files.closeAll()
+	b, _ := ioutil.ReadFile(filepath.Join(root, "ok.log"))
+	if string(b) != "1\n3\n" {
+		t.Errorf("expected lines 1,3 and got %q", b)
+	}
+	b, err = ioutil.ReadFile(filepath.Join(root, DeadLetterPath, DeadLetterFile+".log"))
+	if err != nil {
+		t.Fatalf("failed to read the dead-letter file: %v", err)
+	}
+	var d deadLetter
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(b))), &d); err != nil {
+		t.Fatalf("expected a single JSON line and got %q: %v", b, err)
+	}
+	if d.Filename != "blocked" || d.Line != "2" || d.Error == "" {
+		t.Errorf("expected the dead-lettered request of blocked with line 2 and got %+v", d)
+	}
+}
+
+func TestWriteFailureStatus(t *testing.T) {
+	s, root, stop := failingScribe(t, ErrorPolicy{})
+	defer os.RemoveAll(root)
+	defer close(stop)
+
+	_, err := s.Logger().Log(context.Background(), &pb.LogRequest{Filename: "blocked", Line: "0", Ack: pb.Ack_ACK_WRITTEN})
+	if st := status.Convert(err); st.Code() != codes.FailedPrecondition || !strings.HasPrefix(st.Message(), "failed to write batch: ") {
+		t.Errorf("expected code %v and got '%v'", codes.FailedPrecondition, err)
+	}
+
+	// the errors carrying a status keep it
+	done := make(chan error, 1)
+	s.complete(service.Entry{Done: done}, status.Error(codes.InvalidArgument, "invalid"))
+	if err := <-done; status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected code %v and got '%v'", codes.InvalidArgument, err)
+	}
+}
+
+func TestSkipFailures(t *testing.T) {
+	s, root, stop := failingScribe(t, ErrorPolicy{OnError: OnErrorSkip})
+	defer os.RemoveAll(root)
+	defer close(stop)
+	l := s.Logger()
+
+	for _, line := range []string{"1", "2"} {
+		if _, err := l.Log(context.Background(), &pb.LogRequest{Filename: "blocked", Line: line}); err != nil {
+			t.Fatalf("expected nil error and got '%v'", err)
+		}
+	}
+	_, err := l.Log(context.Background(), &pb.LogRequest{Filename: "ok", Line: "3", Ack: pb.Ack_ACK_WRITTEN})
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for s.Errors().Skipped != 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	exp := ErrorStats{Failed: 2, Skipped: 2}
+	if errs := s.Errors(); errs != exp {
+		t.Errorf("expected %+v and got %+v", exp, errs)
+	}
+	if _, err := os.Stat(filepath.Join(root, DeadLetterPath)); !os.IsNotExist(err) {
+		t.Errorf("expected no dead-letter file")
+	}
+}
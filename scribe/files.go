@@ -0,0 +1,51 @@
+package scribe
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+)
+
+// listFiles describes the logical files under the root path
+// whose path is prefix or lies under it.
+func listFiles(rootPath, prefix string) ([]*pb.ListFilesResponse_File, error) {
+	files, err := logicalFiles(rootPath, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*pb.ListFilesResponse_File, 0, len(files))
+	for _, f := range files {
+		segs, err := segments(rootPath, f.path, f.filename)
+		if err != nil {
+			return nil, err
+		}
+		file := &pb.ListFilesResponse_File{
+			Path:     f.path,
+			Filename: f.filename,
+		}
+		for _, seg := range segs {
+			info, err := os.Stat(seg.file)
+			if os.IsNotExist(err) {
+				// rotated or deleted in the meantime
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("couldn't stat segment '%s': %w", seg.file, err)
+			}
+			if filepath.Base(seg.file) == f.filename+".log" {
+				file.Size = info.Size()
+			} else {
+				file.Segments++
+			}
+			file.TotalBytes += info.Size()
+			if t := info.ModTime().UnixNano(); t > file.LastWrite {
+				file.LastWrite = t
+			}
+		}
+		list = append(list, file)
+	}
+	return list, nil
+}
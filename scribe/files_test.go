@@ -0,0 +1,35 @@
+package scribe
+
+import (
+	"os"
+	"testing"
+)
+
+func TestListFiles(t *testing.T) {
+	root := createSegments(t)
+	defer os.RemoveAll(root)
+
+	files, err := listFiles(root, "")
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("expected 3 files and got %d", len(files))
+	}
+	app := files[0]
+	if app.GetFilename() != "app" {
+		t.Fatalf("expected the first file to be app and got %s", app.GetFilename())
+	}
+	if app.GetSize() != 2 || app.GetSegments() != 2 || app.GetTotalBytes() != 10 {
+		t.Errorf("expected size 2, 2 segments and 10 bytes, got %d, %d and %d",
+			app.GetSize(), app.GetSegments(), app.GetTotalBytes())
+	}
+	if app.GetLastWrite() == 0 {
+		t.Errorf("expected the last write time")
+	}
+
+	files, err = listFiles(root, "none")
+	if err != nil || len(files) != 0 {
+		t.Errorf("expected no files and no error, got %d and '%v'", len(files), err)
+	}
+}
@@ -0,0 +1,133 @@
+package scribe
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+)
+
+const (
+	// FormatRaw persists the line as it was sent
+	FormatRaw = "raw"
+	// FormatJSON persists every request as a JSON object
+	FormatJSON = "json"
+	// FormatText persists every request using a text layout
+	FormatText = "text"
+
+	// DefaultLayout is the text layout used when none is given.
+	// Supported placeholders are {time}, {severity}, {host}, {line} and {fields}.
+	DefaultLayout = "{time} [{severity}] {host} {line} {fields}"
+
+	timeLayout = time.RFC3339Nano
+	// empty is the text rendered for missing values
+	empty = "-"
+)
+
+// Formatter renders a request to the line persisted by the scribe
+type Formatter interface {
+	Format(r *pb.LogRequest) string
+}
+
+// NewFormatter creates the Formatter for the given format.
+// The layout is used only by the text format.
+func NewFormatter(format, layout string) (Formatter, error) {
+	switch format {
+	case "", FormatRaw:
+		return rawFormatter{}, nil
+	case FormatJSON:
+		return jsonFormatter{}, nil
+	case FormatText:
+		if layout == "" {
+			layout = DefaultLayout
+		}
+		return textFormatter{layout: layout}, nil
+	default:
+		return nil, fmt.Errorf("unknown log format '%s'", format)
+	}
+}
+
+type rawFormatter struct{}
+
+func (rawFormatter) Format(r *pb.LogRequest) string {
+	return r.GetLine()
+}
+
+type jsonFormatter struct{}
+
+type jsonLine struct {
+	Time     string            `json:"time"`
+	Severity string            `json:"severity,omitempty"`
+	Host     string            `json:"host,omitempty"`
+	Line     string            `json:"line"`
+	Fields   map[string]string `json:"fields,omitempty"`
+}
+
+func (jsonFormatter) Format(r *pb.LogRequest) string {
+	l := jsonLine{
+		Time:   timestamp(r).Format(timeLayout),
+		Host:   r.GetHost(),
+		Line:   strings.TrimSuffix(r.GetLine(), "\n"),
+		Fields: r.GetFields(),
+	}
+	if r.GetSeverity() != pb.Severity_UNSPECIFIED {
+		l.Severity = r.GetSeverity().String()
+	}
+	b, err := json.Marshal(l)
+	if err != nil {
+		// a struct of strings can't fail to marshal, but just in case
+		return r.GetLine()
+	}
+	return string(b)
+}
+
+type textFormatter struct {
+	layout string
+}
+
+func (f textFormatter) Format(r *pb.LogRequest) string {
+	severity := empty
+	if r.GetSeverity() != pb.Severity_UNSPECIFIED {
+		severity = r.GetSeverity().String()
+	}
+	host := empty
+	if r.GetHost() != "" {
+		host = r.GetHost()
+	}
+
+	rep := strings.NewReplacer(
+		"{time}", timestamp(r).Format(timeLayout),
+		"{severity}", severity,
+		"{host}", host,
+		"{line}", strings.TrimSuffix(r.GetLine(), "\n"),
+		"{fields}", fields(r.GetFields()),
+	)
+	return strings.TrimRight(rep.Replace(f.layout), " ")
+}
+
+// timestamp returns the client's timestamp or,
+// when the client didn't send one, the current time.
+func timestamp(r *pb.LogRequest) time.Time {
+	if r.GetTimestamp() == 0 {
+		return time.Now()
+	}
+	return time.Unix(0, r.GetTimestamp())
+}
+
+// fields renders the fields as key=value pairs sorted by key
+func fields(f map[string]string) string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", k, f[k]))
+	}
+	return strings.Join(pairs, " ")
+}
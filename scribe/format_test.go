@@ -0,0 +1,72 @@
+package scribe
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+)
+
+func TestNewFormatter(t *testing.T) {
+	var tests = []struct {
+		format string
+		err    bool
+	}{
+		{"", false},
+		{FormatRaw, false},
+		{FormatJSON, false},
+		{FormatText, false},
+		{"xml", true},
+	}
+	for _, tt := range tests {
+		_, err := NewFormatter(tt.format, "")
+		if err != nil && !tt.err {
+			t.Errorf("for '%s' expecting no err, got error %v", tt.format, err)
+		}
+		if err == nil && tt.err {
+			t.Errorf("for '%s' expecting err, got no error", tt.format)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	ts := time.Date(2018, 9, 1, 10, 0, 0, 0, time.UTC)
+	structured := &pb.LogRequest{
+		Line:      "something happened\n",
+		Severity:  pb.Severity_WARNING,
+		Timestamp: ts.UnixNano(),
+		Host:      "host1",
+		Fields:    map[string]string{"user": "romanos", "id": "42"},
+	}
+	plain := &pb.LogRequest{Line: "plain line", Timestamp: ts.UnixNano()}
+	tsText := time.Unix(0, ts.UnixNano()).Format(timeLayout)
+
+	var tests = []struct {
+		name   string
+		format string
+		layout string
+		req    *pb.LogRequest
+		exp    string
+	}{
+		{"raw", FormatRaw, "", structured, "something happened\n"},
+		{"json", FormatJSON, "", structured,
+			`{"time":"` + tsText + `","severity":"WARNING","host":"host1","line":"something happened","fields":{"id":"42","user":"romanos"}}`},
+		{"json plain", FormatJSON, "", plain, `{"time":"` + tsText + `","line":"plain line"}`},
+		{"text", FormatText, "", structured,
+			tsText + ` [WARNING] host1 something happened id="42" user="romanos"`},
+		{"text plain", FormatText, "", plain, tsText + " [-] - plain line"},
+		{"text layout", FormatText, "{severity}: {line}", structured, "WARNING: something happened"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFormatter(tt.format, tt.layout)
+			if err != nil {
+				t.Fatalf("expecting no err, got error %v", err)
+			}
+			got := f.Format(tt.req)
+			if got != tt.exp {
+				t.Errorf("expected %q and got %q", tt.exp, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+// +build !windows
+
+package scribe
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// freeSpace returns the bytes available to the scribe
+// and the size of the file system holding path
+func freeSpace(path string) (uint64, uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, 0, fmt.Errorf("couldn't stat file system of '%s': %w", path, err)
+	}
+	return st.Bavail * uint64(st.Bsize), st.Blocks * uint64(st.Bsize), nil
+}
@@ -1,16 +1,18 @@
 package scribe
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
-	"syscall"
+	"time"
 
-	"github.com/RomanosTrechlis/go-scribe/internal/util/format/time"
+	pb "github.com/RomanosTrechlis/go-scribe/api"
 	"github.com/RomanosTrechlis/go-scribe/internal/util/fs"
+	"github.com/RomanosTrechlis/go-scribe/service"
 )
 
 // CheckPath checks the validity of a given path
@@ -22,69 +24,114 @@ func CheckPath(path string) error {
 	return nil
 }
 
-func fileExceedsMaxSize(info os.FileInfo, maxSize int64, rootPath, path, filename string) (bool, error) {
-	if info == nil {
-		return false, nil
-	}
-	// never change filename due to size constraints
-	if maxSize < 0 {
-		return false, nil
-	}
-
-	if info.Size() < maxSize {
-		return false, nil
+// rotate renames the current segment of the file to filename_<timestamp>-<sequence>.log,
+// where timestamp is the time at, the end of the segment, and sequence is the first one
+// from seq whose segment doesn't exist, so that no segment gets overwritten.
+// It returns the new path of the segment and its sequence.
+func rotate(rootPath, path, filename string, at time.Time, seq int) (string, int, error) {
+	dir := filepath.Join(rootPath, path)
+	oldPath := fmt.Sprintf("%s/%s.log", dir, filename)
+	for ; ; seq++ {
+		newPath := filepath.Join(dir, segmentName(filename, at, seq))
+		if exists(newPath) || exists(newPath+gzipExt) {
+			continue
+		}
+		return newPath, seq, replace(oldPath, newPath)
 	}
+}
 
-	oldPath := fmt.Sprintf("%s/%s.log", filepath.Join(rootPath, path), filename)
-	newPath := fmt.Sprintf("%s/%s_%v.log", filepath.Join(rootPath, path), filename, ftime.PrintTime(layout))
-
-	err := replace(oldPath, newPath)
-	if err != nil {
-		return false, fmt.Errorf("failed to rename file exceeding %dbytes: %v", maxSize, err)
-	}
-	return true, nil
+// exists checks if the file exists
+func exists(file string) bool {
+	_, err := os.Stat(file)
+	return !os.IsNotExist(err)
 }
 
-func writeLine(rootPath, path, filename, line string, maxSize int64) error {
-	logPath := fmt.Sprintf("%s/%s/%s.log", rootPath, path, filename)
-	info, err := os.Stat(logPath)
-	if os.IsNotExist(err) {
-		// path doesn't exist and we need to create it.
-		err = os.MkdirAll(filepath.Join(rootPath, path), os.ModePerm)
+// writeBatch writes the entries of a batch grouping them by file,
+// so that every file is appended to only once. Entries that would
+// be written outside the root path fail the batch before any write.
+// The order of the lines for each file is preserved and the
+// written lines are delivered to the followers of each file.
+// The acknowledgement mode of the batch decides whether
+// the lines get flushed, or synced, before returning.
+// Unless nil, flushed is called for every file once its lines are flushed.
+func (t *target) writeBatch(entries []*pb.LogRequest, ack pb.Ack, flushed func()) error {
+	files := make([]string, 0)
+	lines := make(map[string][]*pb.LogRequest)
+	for _, e := range entries {
+		path, err := service.ValidateFile("", e.GetPath(), e.GetFilename())
 		if err != nil {
-			return fmt.Errorf("couldn't create path '%s': %v",
-				filepath.Join(rootPath, path), err)
+			return err
 		}
+		e.Path = path
+		key := filepath.Join(e.GetPath(), e.GetFilename())
+		if _, ok := lines[key]; !ok {
+			files = append(files, key)
+		}
+		lines[key] = append(lines[key], e)
 	}
 
-	createNewFile, err := fileExceedsMaxSize(info, maxSize, rootPath, path, filename)
-	if err != nil {
-		return fmt.Errorf("failed to rename file: %v", err)
-	}
-	if createNewFile {
-		// re create file if the old has exceeded max size
-		err = os.MkdirAll(filepath.Join(rootPath, path), os.ModePerm)
+	for _, key := range files {
+		reqs := lines[key]
+		l := make([]string, 0, len(reqs))
+		for _, r := range reqs {
+			l = append(l, strings.TrimSuffix(t.formatter.Format(r), "\n"))
+		}
+		err := t.tails.write(key, l, func() error {
+			return t.files.write(t.rootPath, reqs[0].GetPath(), reqs[0].GetFilename(), l, t.fileSize, ack, flushed)
+		})
 		if err != nil {
-			return fmt.Errorf("couldn't create path '%s': %v",
-				filepath.Join(rootPath, path), err)
+			return err
 		}
 	}
+	return nil
+}
 
-	f, err := os.OpenFile(logPath,
-		syscall.O_CREAT|syscall.O_APPEND|syscall.O_WRONLY, os.ModePerm)
+// lastLines reads the last n lines of a file.
+// A file that doesn't exist has no lines.
+func lastLines(file string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
 	if err != nil {
-		return fmt.Errorf("couldn't create to path '%s': %v", logPath, err)
+		return nil, fmt.Errorf("couldn't open file '%s': %v", file, err)
 	}
 	defer f.Close()
 
-	if !strings.HasSuffix(line, "\n") {
-		line += "\n"
-	}
-	_, err = f.WriteString(line)
+	info, err := f.Stat()
 	if err != nil {
-		return fmt.Errorf("couldn't write line: %v", err)
+		return nil, fmt.Errorf("couldn't stat file '%s': %v", file, err)
 	}
-	return nil
+
+	// read backwards, a chunk at a time, until there are enough lines
+	const chunk = 4096
+	offset := info.Size()
+	var buf []byte
+	for offset > 0 && bytes.Count(buf, []byte("\n")) <= n {
+		size := int64(chunk)
+		if offset < size {
+			size = offset
+		}
+		offset -= size
+		b := make([]byte, size)
+		_, err := f.ReadAt(b, offset)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't read file '%s': %v", file, err)
+		}
+		buf = append(b, buf...)
+	}
+	if len(buf) == 0 {
+		return nil, nil
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(buf), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
 }
 
 func replace(oldpath, newpath string) error {
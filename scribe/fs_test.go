@@ -1,87 +1,52 @@
 package scribe
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
-	"time"
 
 	pb "github.com/RomanosTrechlis/go-scribe/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-// mockStat is a dummy implementation of FileInfo returned by Stat and Lstat.
-type mockStat struct {
-	name    string
-	size    int64
-	mode    os.FileMode
-	modTime time.Time
-	isDir   bool
-}
-
-func (ms mockStat) Size() int64        { return ms.size }
-func (ms mockStat) Mode() os.FileMode  { return ms.mode }
-func (ms mockStat) ModTime() time.Time { return ms.modTime }
-func (ms mockStat) Sys() interface{}   { return "" }
-func (ms mockStat) Name() string       { return ms.name }
-func (ms mockStat) IsDir() bool        { return ms.isDir }
-
-func TestFileExceedsMaxSize(t *testing.T) {
-	type testCase struct {
+func TestWriteMaxSize(t *testing.T) {
+	var tc = []struct {
 		maxSize int64
-		ms      mockStat
-		req     *pb.LogRequest
-		exp     bool
-		err     bool
-	}
-	var tc = []testCase{
-		{
-			maxSize: 500,
-			ms:      mockStat{name: "test1", size: 100, mode: os.ModePerm, modTime: time.Now()},
-			req:     &pb.LogRequest{},
-			exp:     false,
-			err:     false,
-		},
+		size    int
+		rotated int
+	}{
+		{500, 100, 0},
 		// infinite file size
-		{
-			maxSize: -1,
-			ms:      mockStat{name: "test2", size: 100, mode: os.ModePerm, modTime: time.Now()},
-			req:     &pb.LogRequest{},
-			exp:     false,
-			err:     false,
-		},
-		{
-			maxSize: 100,
-			ms:      mockStat{name: "test3", size: 101, mode: os.ModePerm, modTime: time.Now()},
-			req:     &pb.LogRequest{},
-			exp:     false,
-			err:     true,
-		},
-		{
-			maxSize: 100,
-			ms:      mockStat{name: "test3", size: 101, mode: os.ModePerm, modTime: time.Now()},
-			req:     &pb.LogRequest{Path: "testdata", Filename: "1"},
-			exp:     true,
-			err:     false,
-		},
-	}
-
-	os.Mkdir("testdata", os.ModePerm)
-	os.Create("testdata/1.log")
-	for _, m := range tc {
-		b, e := fileExceedsMaxSize(m.ms, m.maxSize, "./", m.req.GetPath(), m.req.GetFilename())
-		if m.err && e == nil {
-			t.Errorf("Expected err and got no error")
+		{-1, 100, 0},
+		{100, 101, 1},
+		{100, 100, 1},
+	}
+	for _, tt := range tc {
+		root, err := ioutil.TempDir("", "size")
+		if err != nil {
+			t.Fatalf("failed to create temp dir: %v", err)
 		}
-		if !m.err && e != nil {
-			t.Errorf("Expected nil error and got '%v'", e)
+		defer os.RemoveAll(root)
+		ioutil.WriteFile(filepath.Join(root, "1.log"), []byte(strings.Repeat("x", tt.size)), 0644)
+
+		err = newFileCache(0, 0).write(root, "", "1", []string{"line"}, tt.maxSize, pb.Ack_ACK_WRITTEN, nil)
+		if err != nil {
+			t.Fatalf("expected nil error and got '%v'", err)
 		}
-		if m.exp && !b {
-			t.Errorf("Expected %v and got %v", m.exp, b)
+		segments, _ := filepath.Glob(filepath.Join(root, "1_*.log"))
+		if len(segments) != tt.rotated {
+			t.Errorf("expected %d rotated segments for size %d and max %d and got %d",
+				tt.rotated, tt.size, tt.maxSize, len(segments))
 		}
-		if !m.exp && b {
-			t.Errorf("Expected %v and got %v", m.exp, b)
+		for _, seg := range segments {
+			if _, seq, ok := rotationTime(filepath.Base(seg), "1"); !ok || seq != 0 {
+				t.Errorf("expected the first sequence for %s", seg)
+			}
 		}
 	}
-	os.RemoveAll("testdata/")
 }
 
 func TestCheckPath(t *testing.T) {
@@ -110,53 +75,128 @@ func TestCheckPath(t *testing.T) {
 	os.Remove("file.txt")
 }
 
-func TestWriteLine(t *testing.T) {
-	type testCase struct {
+func TestWrite(t *testing.T) {
+	root, err := ioutil.TempDir("", "write")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	var tc = []struct {
 		req     *pb.LogRequest
 		path    string
 		maxSize int64
-		err     bool
-	}
-
-	s := "testdata"
-	var tc = []testCase{
+	}{
 		// path doesn't exists
-		{
-			req:  &pb.LogRequest{},
-			path: "noPath",
-			err:  false,
-		},
-		{
-			req:     &pb.LogRequest{Filename: "l"},
-			path:    s,
-			err:     false,
-			maxSize: 1,
-		},
-		{
-			req:  &pb.LogRequest{},
-			path: s,
-			err:  false,
-		},
+		{req: &pb.LogRequest{}, path: "noPath"},
+		{req: &pb.LogRequest{Filename: "l"}, path: "testdata", maxSize: 1},
+		{req: &pb.LogRequest{}, path: "testdata"},
 		// has \n
-		{
-			req:     &pb.LogRequest{Filename: "l", Line: "This is another test\n"},
-			path:    s,
-			err:     false,
-			maxSize: 1,
-		},
-	}
-	os.Mkdir(s, os.ModePerm)
-	f, _ := os.Create(s + "/l.log")
-	f.WriteString("This is a test")
+		{req: &pb.LogRequest{Filename: "l", Line: "This is another test\n"}, path: "testdata", maxSize: 1},
+	}
+	os.Mkdir(filepath.Join(root, "testdata"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(root, "testdata", "l.log"), []byte("This is a test"), 0644)
+	files := newFileCache(0, 0)
 	for _, m := range tc {
-		err := writeLine(m.path+"/", m.req.GetPath(), m.req.GetFilename(), m.req.GetLine(), m.maxSize)
-		if m.err && err == nil {
-			t.Errorf("Expected err and got no error")
-		}
-		if !m.err && err != nil {
+		err := files.write(filepath.Join(root, m.path), m.req.GetPath(), m.req.GetFilename(),
+			[]string{m.req.GetLine()}, m.maxSize, pb.Ack_ACK_WRITTEN, nil)
+		if err != nil {
 			t.Errorf("Expected nil error and got '%v'", err)
 		}
 	}
-	os.RemoveAll("testdata/")
-	os.RemoveAll("noPath/")
+}
+
+func TestWriteBatch(t *testing.T) {
+	root, err := ioutil.TempDir("", "batch")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	entries := []*pb.LogRequest{
+		{Filename: "a", Line: "1"},
+		{Filename: "b", Path: "p", Line: "2"},
+		{Filename: "a", Line: "3\n"},
+		{Filename: "b", Path: "p", Line: "4"},
+	}
+	tg := &target{rootPath: root, fileSize: -1, formatter: rawFormatter{}, tails: newTailHub(), files: newFileCache(0, 0)}
+	err = tg.writeBatch(entries, pb.Ack_ACK_SYNCED, nil)
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+
+	var tc = []struct {
+		file string
+		exp  string
+	}{
+		{filepath.Join(root, "a.log"), "1\n3\n"},
+		{filepath.Join(root, "p", "b.log"), "2\n4\n"},
+	}
+	for _, tt := range tc {
+		b, err := ioutil.ReadFile(tt.file)
+		if err != nil {
+			t.Errorf("failed to read %s: %v", tt.file, err)
+			continue
+		}
+		if string(b) != tt.exp {
+			t.Errorf("expected %q in %s and got %q", tt.exp, tt.file, string(b))
+		}
+	}
+}
+
+func TestWriteBatchOutsideRoot(t *testing.T) {
+	root, err := ioutil.TempDir("", "batch")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	entries := []*pb.LogRequest{
+		{Filename: "a", Line: "1"},
+		{Filename: "escaped", Path: "../", Line: "2"},
+	}
+	tg := &target{rootPath: filepath.Join(root, "logs"), fileSize: -1, formatter: rawFormatter{},
+		tails: newTailHub(), files: newFileCache(0, 0)}
+	err = tg.writeBatch(entries, pb.Ack_ACK_SYNCED, nil)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected code %v and got '%v'", codes.InvalidArgument, err)
+	}
+	// no entry of the batch is written
+	for _, file := range []string{filepath.Join(root, "logs", "a.log"), filepath.Join(root, "escaped.log")} {
+		if _, err := os.Stat(file); !os.IsNotExist(err) {
+			t.Errorf("expected %s not to be written", file)
+		}
+	}
+}
+
+func TestLastLines(t *testing.T) {
+	root, err := ioutil.TempDir("", "last")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	file := filepath.Join(root, "f.log")
+	long := strings.Repeat("x", 5000)
+	ioutil.WriteFile(file, []byte("1\n2\n"+long+"\n3\n"), 0644)
+
+	var tc = []struct {
+		file string
+		n    int
+		exp  []string
+	}{
+		{file, 0, nil},
+		{file, 2, []string{long, "3"}},
+		{file, 10, []string{"1", "2", long, "3"}},
+		{filepath.Join(root, "none.log"), 2, nil},
+	}
+	for _, tt := range tc {
+		lines, err := lastLines(tt.file, tt.n)
+		if err != nil {
+			t.Errorf("expected nil error and got '%v'", err)
+		}
+		if strings.Join(lines, ",") != strings.Join(tt.exp, ",") || len(lines) != len(tt.exp) {
+			t.Errorf("expected %d lines and got %d", len(tt.exp), len(lines))
+		}
+	}
 }
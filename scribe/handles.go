@@ -0,0 +1,447 @@
+package scribe
+
+import (
+	"bufio"
+	"container/list"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	p "github.com/RomanosTrechlis/go-scribe/internal/util/format/print"
+)
+
+const (
+	// defaultMaxOpenFiles is the number of files the scribe keeps open
+	defaultMaxOpenFiles = 256
+	// defaultIdleTimeout is the time a file stays open without writes
+	defaultIdleTimeout = time.Minute
+)
+
+// openFile is the handle of the current segment of a logical file.
+// mu is held while using the file, so that the cache doesn't have to
+// be locked while writing and different files get written in parallel.
+// Callers holding both lock the cache first. The cache lock guards used.
+type openFile struct {
+	mu   sync.Mutex
+	key  string
+	f    *os.File
+	size int64
+	used time.Time
+	// w buffers the lines when the flush policy isn't line
+	w *bufio.Writer
+	// dirty is set when there are lines not synced to disk
+	dirty bool
+	// period is the start of the rotation period of the lines of the file
+	period time.Time
+	// flushed are called once the lines buffered so far are flushed
+	flushed []func()
+}
+
+func (of *openFile) write(s string) error {
+	var err error
+	if of.w != nil {
+		_, err = of.w.WriteString(s)
+	} else {
+		_, err = of.f.WriteString(s)
+	}
+	of.size += int64(len(s))
+	of.dirty = true
+	if err != nil {
+		return fmt.Errorf("couldn't write line: %w", err)
+	}
+	return nil
+}
+
+// flush writes the buffered lines to the file
+func (of *openFile) flush() error {
+	if of.w != nil && of.w.Buffered() > 0 {
+		if err := of.w.Flush(); err != nil {
+			return fmt.Errorf("couldn't write line: %w", err)
+		}
+	}
+	for _, fn := range of.flushed {
+		fn()
+	}
+	of.flushed = nil
+	return nil
+}
+
+// onFlush calls fn once the lines written so far are flushed,
+// right away when none is buffered.
+func (of *openFile) onFlush(fn func()) {
+	if of.w == nil || of.w.Buffered() == 0 {
+		fn()
+		return
+	}
+	of.flushed = append(of.flushed, fn)
+}
+
+// sync flushes the buffered lines and syncs the file to disk
+func (of *openFile) sync() error {
+	if err := of.flush(); err != nil {
+		return err
+	}
+	if !of.dirty {
+		return nil
+	}
+	if err := of.f.Sync(); err != nil {
+		return fmt.Errorf("couldn't sync file '%s': %w", of.f.Name(), err)
+	}
+	of.dirty = false
+	return nil
+}
+
+// lastRotation is the second, since the epoch, and the sequence of a rotation
+type lastRotation struct {
+	at  int64
+	seq int
+}
+
+// fileCache keeps the handles of the files written lately open,
+// so that a write doesn't have to stat, open and close the file.
+// It holds at most max handles, closing the least recently used one
+// when full, and closes the handles not written for idle.
+// A cache with max zero closes every handle after writing.
+//
+// The policy defines whether the lines of an open file get buffered
+// and when they get flushed and synced. Buffered lines are flushed
+// when their file gets closed, rotated or read.
+type fileCache struct {
+	mu     sync.Mutex
+	max    int
+	idle   time.Duration
+	policy writePolicy
+	// rotation defines when the files rotate on time
+	rotation rotation
+	// compressor compresses the rotated segments, when not nil
+	compressor *compressor
+	// rotated has the latest rotation of every file rotated
+	rotated map[string]lastRotation
+	// order has the most recently used handle in front
+	order *list.List
+	// files has as key filepath.Join(path, filename)
+	// and value its element in order
+	files map[string]*list.Element
+	// closed is set by closeAll, after which no handle is kept
+	closed bool
+	// janitor is started with the first handle kept
+	janitor sync.Once
+	stop    chan struct{}
+}
+
+func newFileCache(max int, idle time.Duration) *fileCache {
+	return &fileCache{
+		max:     max,
+		idle:    idle,
+		policy:  defaultWritePolicy(),
+		order:   list.New(),
+		files:   make(map[string]*list.Element),
+		rotated: make(map[string]lastRotation),
+		stop:    make(chan struct{}),
+	}
+}
+
+// write appends the lines to the current segment of the file, rotating
+// the segment first when it has reached maxSize or its period has ended.
+// Lines asking for ACK_WRITTEN are flushed to the file and lines
+// asking for ACK_SYNCED are synced to disk before returning.
+// Unless nil, flushed is called once the lines are flushed, which
+// never happens when the write fails or the flush of the file does.
+// Only the handle of the file is locked while writing.
+func (c *fileCache) write(rootPath, path, filename string, lines []string, maxSize int64, ack pb.Ack, flushed func()) error {
+	key := filepath.Join(path, filename)
+	of, err := c.acquire(rootPath, path, filename, key, maxSize)
+	if err != nil {
+		return err
+	}
+
+	var buf strings.Builder
+	for _, line := range lines {
+		buf.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			buf.WriteString("\n")
+		}
+	}
+	err = of.write(buf.String())
+	if err == nil && (ack == pb.Ack_ACK_SYNCED || c.policy.sync == SyncAlways) {
+		err = of.sync()
+	} else if err == nil && ack == pb.Ack_ACK_WRITTEN {
+		err = of.flush()
+	}
+	if err == nil && flushed != nil {
+		of.onFlush(flushed)
+	}
+	of.mu.Unlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		// the next write opens the file again
+		c.release(key, of)
+		return err
+	}
+	if c.max <= 0 || c.closed {
+		return c.release(key, of)
+	}
+	return nil
+}
+
+// acquire returns the handle of the current segment of the file locked,
+// rotating the segment first when it has reached maxSize or its period
+// has ended.
+func (c *fileCache) acquire(rootPath, path, filename, key string, maxSize int64) (*openFile, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	of, err := c.get(rootPath, path, filename, key)
+	if err != nil {
+		return nil, err
+	}
+	of.mu.Lock()
+	var at time.Time
+	now := time.Now()
+	// never change filename due to size constraints when maxSize is negative
+	if maxSize >= 0 && of.size >= maxSize {
+		at = now
+	}
+	if c.rotation.enabled() && !c.rotation.start(now).Equal(of.period) {
+		at = c.rotation.end(of.period)
+	}
+	if at.IsZero() {
+		return of, nil
+	}
+
+	of.mu.Unlock()
+	if err := c.remove(key); err != nil {
+		return nil, err
+	}
+	// the sequence increases for the segments rotated during the same second
+	seq := 0
+	if last, ok := c.rotated[key]; ok && last.at == at.Unix() {
+		seq = last.seq + 1
+	}
+	rotated, seq, err := rotate(rootPath, path, filename, at, seq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate file '%s': %v", key, err)
+	}
+	c.rotated[key] = lastRotation{at: at.Unix(), seq: seq}
+	c.compressor.enqueue(rotated)
+	of, err = c.get(rootPath, path, filename, key)
+	if err != nil {
+		return nil, err
+	}
+	of.mu.Lock()
+	return of, nil
+}
+
+// release closes the handle of key, unless it got closed meanwhile.
+// Callers must hold mu.
+func (c *fileCache) release(key string, of *openFile) error {
+	if e, ok := c.files[key]; !ok || e.Value.(*openFile) != of {
+		return nil
+	}
+	return c.remove(key)
+}
+
+// get returns the handle of the file, opening it when it isn't cached
+// and closing the least recently used handles when the cache is full.
+// Callers must hold mu.
+func (c *fileCache) get(rootPath, path, filename, key string) (*openFile, error) {
+	if e, ok := c.files[key]; ok {
+		c.order.MoveToFront(e)
+		of := e.Value.(*openFile)
+		of.used = time.Now()
+		return of, nil
+	}
+
+	dir := filepath.Join(rootPath, path)
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create path '%s': %w", dir, err)
+	}
+	logPath := fmt.Sprintf("%s/%s/%s.log", rootPath, path, filename)
+	f, err := os.OpenFile(logPath,
+		syscall.O_CREAT|syscall.O_APPEND|syscall.O_WRONLY, os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create to path '%s': %w", logPath, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("couldn't stat file '%s': %w", logPath, err)
+	}
+
+	of := &openFile{key: key, f: f, size: info.Size(), used: time.Now()}
+	if c.rotation.enabled() {
+		// the lines of an existing file were written up to its last modification
+		written := info.ModTime()
+		if of.size == 0 {
+			written = of.used
+		}
+		of.period = c.rotation.start(written)
+	}
+	if c.policy.buffered() && c.max > 0 {
+		of.w = bufio.NewWriterSize(f, c.policy.bufferSize)
+	}
+	c.files[key] = c.order.PushFront(of)
+	for c.max > 0 && c.order.Len() > c.max {
+		evicted := c.order.Back().Value.(*openFile).key
+		if err := c.remove(evicted); err != nil {
+			p.Print(fmt.Sprintf("failed to close file %s: %v", evicted, err))
+		}
+	}
+	if c.max > 0 && !c.closed {
+		c.janitor.Do(func() { go c.run() })
+	}
+	return of, nil
+}
+
+// remove flushes, and unless the sync policy is never syncs,
+// the file of key and closes its handle. Callers must hold mu.
+func (c *fileCache) remove(key string) error {
+	e, ok := c.files[key]
+	if !ok {
+		return nil
+	}
+	c.order.Remove(e)
+	delete(c.files, key)
+	of := e.Value.(*openFile)
+	of.mu.Lock()
+	defer of.mu.Unlock()
+	var err error
+	if c.policy.sync == SyncNever {
+		err = of.flush()
+	} else {
+		err = of.sync()
+	}
+	of.f.Close()
+	return err
+}
+
+// run closes the idle handles and, depending on the policy,
+// flushes and syncs the open files until the cache gets closed.
+func (c *fileCache) run() {
+	// disabled tickers leave their channel nil
+	var idleTick, flushTick, syncTick <-chan time.Time
+	if c.idle > 0 {
+		// every half of idle the handles not used for idle get closed
+		t := time.NewTicker(c.idle / 2)
+		defer t.Stop()
+		idleTick = t.C
+	}
+	if c.policy.flush == FlushInterval {
+		t := time.NewTicker(c.policy.flushInterval)
+		defer t.Stop()
+		flushTick = t.C
+	}
+	if c.policy.sync == SyncInterval {
+		t := time.NewTicker(c.policy.syncInterval)
+		defer t.Stop()
+		syncTick = t.C
+	}
+
+	for {
+		select {
+		case <-idleTick:
+			c.mu.Lock()
+			for e := c.order.Back(); e != nil; e = c.order.Back() {
+				of := e.Value.(*openFile)
+				if time.Since(of.used) < c.idle {
+					break
+				}
+				if err := c.remove(of.key); err != nil {
+					p.Print(fmt.Sprintf("failed to close file %s: %v", of.key, err))
+				}
+			}
+			c.mu.Unlock()
+		case <-flushTick:
+			if err := c.flushAll(); err != nil {
+				p.Print(err.Error())
+			}
+		case <-syncTick:
+			if err := c.syncAll(); err != nil {
+				p.Print(err.Error())
+			}
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// flush writes the buffered lines of the file of key,
+// filepath.Join(path, filename), to the file.
+func (c *fileCache) flush(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.files[key]
+	if !ok {
+		return nil
+	}
+	of := e.Value.(*openFile)
+	of.mu.Lock()
+	defer of.mu.Unlock()
+	return of.flush()
+}
+
+// flushAll writes the buffered lines of every open file to the file
+func (c *fileCache) flushAll() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var failed error
+	for e := c.order.Front(); e != nil; e = e.Next() {
+		of := e.Value.(*openFile)
+		of.mu.Lock()
+		if err := of.flush(); err != nil {
+			failed = err
+		}
+		of.mu.Unlock()
+	}
+	return failed
+}
+
+// syncAll flushes and syncs to disk every open file
+func (c *fileCache) syncAll() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var failed error
+	for e := c.order.Front(); e != nil; e = e.Next() {
+		of := e.Value.(*openFile)
+		of.mu.Lock()
+		if err := of.sync(); err != nil {
+			failed = err
+		}
+		of.mu.Unlock()
+	}
+	return failed
+}
+
+// count returns the number of open handles
+func (c *fileCache) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// closeAll flushes and closes every handle. Writes after
+// closing still succeed but close their handle right away.
+func (c *fileCache) closeAll() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	close(c.stop)
+	var failed error
+	for c.order.Len() > 0 {
+		if err := c.remove(c.order.Back().Value.(*openFile).key); err != nil {
+			failed = err
+		}
+	}
+	return failed
+}
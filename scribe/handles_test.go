@@ -0,0 +1,191 @@
+package scribe
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+)
+
+func TestFileCache(t *testing.T) {
+	root, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	c := newFileCache(2, 0)
+	for _, name := range []string{"a", "b", "a", "c"} {
+		if err := c.write(root, "p", name, []string{name}, -1, pb.Ack_ACK_RECEIVED, nil); err != nil {
+			t.Fatalf("expected nil error and got '%v'", err)
+		}
+	}
+	// b is the least recently used file
+	if _, ok := c.files[filepath.Join("p", "b")]; ok || c.count() != 2 {
+		t.Errorf("expected a and c to be open and got %d files", c.count())
+	}
+
+	c.closeAll()
+	if c.count() != 0 {
+		t.Errorf("expected no open files after closing and got %d", c.count())
+	}
+	// writes after closing don't keep their files open
+	if err := c.write(root, "p", "a", []string{"last"}, -1, pb.Ack_ACK_RECEIVED, nil); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	if c.count() != 0 {
+		t.Errorf("expected no open files after closing and got %d", c.count())
+	}
+
+	var tc = []struct {
+		file string
+		exp  string
+	}{
+		{filepath.Join(root, "p", "a.log"), "a\na\nlast\n"},
+		{filepath.Join(root, "p", "b.log"), "b\n"},
+		{filepath.Join(root, "p", "c.log"), "c\n"},
+	}
+	for _, tt := range tc {
+		b, err := ioutil.ReadFile(tt.file)
+		if err != nil {
+			t.Errorf("failed to read %s: %v", tt.file, err)
+			continue
+		}
+		if string(b) != tt.exp {
+			t.Errorf("expected %q in %s and got %q", tt.exp, tt.file, string(b))
+		}
+	}
+}
+
+func TestFileCacheRotation(t *testing.T) {
+	root, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	c := newFileCache(1, 0)
+	defer c.closeAll()
+	// the first write fills the file, the second rotates it
+	for _, line := range []string{"0123456789", "next"} {
+		if err := c.write(root, "", "r", []string{line}, 10, pb.Ack_ACK_RECEIVED, nil); err != nil {
+			t.Fatalf("expected nil error and got '%v'", err)
+		}
+	}
+
+	segs, err := segments(root, "", "r")
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	if len(segs) != 2 {
+		t.Fatalf("expected 2 segments and got %d", len(segs))
+	}
+	for i, exp := range []string{"0123456789\n", "next\n"} {
+		b, _ := ioutil.ReadFile(segs[i].file)
+		if string(b) != exp {
+			t.Errorf("expected %q in segment %d and got %q", exp, i, string(b))
+		}
+	}
+}
+
+func TestFileCacheIdle(t *testing.T) {
+	root, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	c := newFileCache(10, 20*time.Millisecond)
+	defer c.closeAll()
+	if err := c.write(root, "", "idle", []string{"x"}, -1, pb.Ack_ACK_RECEIVED, nil); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	if c.count() != 1 {
+		t.Fatalf("expected 1 open file and got %d", c.count())
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for c.count() != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if c.count() != 0 {
+		t.Errorf("expected idle file to be closed and got %d open files", c.count())
+	}
+}
+
+func TestFileCacheBuffered(t *testing.T) {
+	root, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	c := newFileCache(10, 0)
+	if err := c.policy.setFlush(FlushInterval, 1024, 20*time.Millisecond); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	defer c.closeAll()
+	file := filepath.Join(root, "buf.log")
+	read := func() string {
+		b, _ := ioutil.ReadFile(file)
+		return string(b)
+	}
+
+	flushed := 0
+	if err := c.write(root, "", "buf", []string{"1"}, -1, pb.Ack_ACK_RECEIVED, func() { flushed++ }); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	if got := read(); got != "" || flushed != 0 {
+		t.Errorf("expected buffered line not to be written and got %q", got)
+	}
+	// lines waiting for the write are flushed along with the buffered ones
+	if err := c.write(root, "", "buf", []string{"2"}, -1, pb.Ack_ACK_WRITTEN, func() { flushed++ }); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	if got := read(); got != "1\n2\n" {
+		t.Errorf("expected written lines and got %q", got)
+	}
+	if flushed != 2 {
+		t.Errorf("expected both writes reported flushed and got %d", flushed)
+	}
+
+	if err := c.write(root, "", "buf", []string{"3"}, -1, pb.Ack_ACK_RECEIVED, nil); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for read() != "1\n2\n3\n" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := read(); got != "1\n2\n3\n" {
+		t.Errorf("expected the interval to flush the line and got %q", got)
+	}
+}
+
+func BenchmarkWrite(b *testing.B) {
+	for _, files := range []int{1, 64} {
+		for _, max := range []int{0, defaultMaxOpenFiles} {
+			name := fmt.Sprintf("files=%d/open=%d", files, max)
+			b.Run(name, func(b *testing.B) {
+				root, err := ioutil.TempDir("", "bench")
+				if err != nil {
+					b.Fatalf("failed to create temp dir: %v", err)
+				}
+				defer os.RemoveAll(root)
+
+				c := newFileCache(max, 0)
+				defer c.closeAll()
+				lines := []string{"a line of a typical length written by the benchmark"}
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					err := c.write(root, "bench", fmt.Sprintf("f%d", i%files), lines, -1, pb.Ack_ACK_RECEIVED, nil)
+					if err != nil {
+						b.Fatalf("failed to write: %v", err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package scribe
+
+import (
+	"fmt"
+	"time"
+)
+
+// Flush policies define when the lines reach the file
+const (
+	// FlushLine writes the lines to the file as they arrive
+	FlushLine = "line"
+	// FlushSize buffers the lines of a file until its buffer is full
+	FlushSize = "size"
+	// FlushInterval buffers the lines of a file until its buffer
+	// is full or the flush interval passes
+	FlushInterval = "interval"
+)
+
+// Sync policies define when the written lines are synced to disk
+const (
+	// SyncNever leaves syncing to the operating system
+	SyncNever = "never"
+	// SyncInterval syncs the files written every sync interval
+	SyncInterval = "interval"
+	// SyncAlways syncs the file after every write
+	SyncAlways = "always"
+)
+
+const (
+	// defaultBufferSize is the size of the buffer of every open file
+	defaultBufferSize = 64 * 1024
+	// defaultPolicyInterval is the flush and sync interval
+	// used when the interval policies are given none
+	defaultPolicyInterval = time.Second
+)
+
+// writePolicy defines how the lines are buffered and synced.
+// Requests asking for ACK_WRITTEN or ACK_SYNCED get flushed,
+// and synced, regardless of the policy.
+type writePolicy struct {
+	flush         string
+	bufferSize    int
+	flushInterval time.Duration
+	sync          string
+	syncInterval  time.Duration
+}
+
+func defaultWritePolicy() writePolicy {
+	return writePolicy{
+		flush:         FlushLine,
+		bufferSize:    defaultBufferSize,
+		flushInterval: defaultPolicyInterval,
+		sync:          SyncNever,
+		syncInterval:  defaultPolicyInterval,
+	}
+}
+
+// buffered checks if the lines are kept in memory before writing
+func (p writePolicy) buffered() bool {
+	return p.flush != FlushLine
+}
+
+// setFlush validates and sets the flush policy. Zero bufferSize
+// and interval keep their defaults.
+func (p *writePolicy) setFlush(policy string, bufferSize int64, interval time.Duration) error {
+	switch policy {
+	case "", FlushLine, FlushSize, FlushInterval:
+	default:
+		return fmt.Errorf("unknown flush policy '%s'", policy)
+	}
+	if bufferSize < 0 || interval < 0 {
+		return fmt.Errorf("invalid buffer size %d and flush interval %v", bufferSize, interval)
+	}
+	if policy != "" {
+		p.flush = policy
+	}
+	if bufferSize > 0 {
+		p.bufferSize = int(bufferSize)
+	}
+	if interval > 0 {
+		p.flushInterval = interval
+	}
+	return nil
+}
+
+// setSync validates and sets the sync policy. Zero interval keeps its default.
+func (p *writePolicy) setSync(policy string, interval time.Duration) error {
+	switch policy {
+	case "", SyncNever, SyncInterval, SyncAlways:
+	default:
+		return fmt.Errorf("unknown sync policy '%s'", policy)
+	}
+	if interval < 0 {
+		return fmt.Errorf("invalid sync interval %v", interval)
+	}
+	if policy != "" {
+		p.sync = policy
+	}
+	if interval > 0 {
+		p.syncInterval = interval
+	}
+	return nil
+}
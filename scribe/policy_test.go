@@ -0,0 +1,47 @@
+package scribe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWritePolicy(t *testing.T) {
+	var tc = []struct {
+		name  string
+		flush string
+		size  int64
+		fint  time.Duration
+		sync  string
+		sint  time.Duration
+		exp   writePolicy
+		err   bool
+	}{
+		{"defaults", "", 0, 0, "", 0, defaultWritePolicy(), false},
+		{"interval", FlushInterval, 4096, time.Minute, SyncInterval, 5 * time.Second,
+			writePolicy{FlushInterval, 4096, time.Minute, SyncInterval, 5 * time.Second}, false},
+		{"size", FlushSize, 0, 0, SyncAlways, 0,
+			writePolicy{FlushSize, defaultBufferSize, defaultPolicyInterval, SyncAlways, defaultPolicyInterval}, false},
+		{"unknown flush", "often", 0, 0, "", 0, writePolicy{}, true},
+		{"unknown sync", "", 0, 0, "sometimes", 0, writePolicy{}, true},
+		{"negative size", FlushSize, -1, 0, "", 0, writePolicy{}, true},
+		{"negative interval", "", 0, 0, SyncInterval, -time.Second, writePolicy{}, true},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			p := defaultWritePolicy()
+			err := p.setFlush(tt.flush, tt.size, tt.fint)
+			if err == nil {
+				err = p.setSync(tt.sync, tt.sint)
+			}
+			if tt.err && err == nil {
+				t.Fatalf("expected error and got nil")
+			}
+			if !tt.err && err != nil {
+				t.Fatalf("expected nil error and got '%v'", err)
+			}
+			if !tt.err && p != tt.exp {
+				t.Errorf("expected %+v and got %+v", tt.exp, p)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package scribe
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	"github.com/RomanosTrechlis/go-scribe/service"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultReadLimit is the number of lines
+	// returned by Read when no limit is given.
+	defaultReadLimit = 1000
+	// maxReadLimit is the maximum number of lines returned by Read
+	maxReadLimit = 10000
+)
+
+// logReader implements the LogReader protobuf service
+// over the files written by the target.
+type logReader struct {
+	*target
+	// id of the scribe, reported with the search results
+	id string
+	// stop is closed when the scribe shuts down
+	stop chan struct{}
+}
+
+// Tail implements the Tail protobuf service
+func (r logReader) Tail(req *pb.TailRequest, stream pb.LogReader_TailServer) error {
+	path, err := service.ValidateFile("", req.GetPath(), req.GetFilename())
+	if err != nil {
+		return err
+	}
+
+	key := filepath.Join(path, req.GetFilename())
+	file := fmt.Sprintf("%s/%s/%s.log", r.rootPath, path, req.GetFilename())
+	lines, backlog, err := r.tails.subscribe(key, func() ([]string, error) {
+		if err := r.files.flush(key); err != nil {
+			return nil, err
+		}
+		return lastLines(file, int(req.GetLines()))
+	})
+	if err != nil {
+		return service.Status(err)
+	}
+	defer r.tails.unsubscribe(key, lines)
+
+	send := func(line string) error {
+		return stream.Send(&pb.LogLine{
+			Filename: req.GetFilename(),
+			Path:     path,
+			Line:     line,
+		})
+	}
+	for _, l := range backlog {
+		if err := send(l); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case l, ok := <-lines:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "client is too slow to follow the file")
+			}
+			if err := send(l); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		case <-r.stop:
+			return service.Unavailable("server is shutting down", 5*time.Second)
+		}
+	}
+}
+
+// Read implements the Read protobuf service
+func (r logReader) Read(ctx context.Context, req *pb.ReadRequest) (*pb.ReadResponse, error) {
+	path, err := service.ValidateFile("", req.GetPath(), req.GetFilename())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetOffset() < 0 {
+		return nil, service.InvalidArgument("offset", "must not be negative")
+	}
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultReadLimit
+	}
+	if limit > maxReadLimit {
+		limit = maxReadLimit
+	}
+
+	err = r.files.flush(filepath.Join(path, req.GetFilename()))
+	if err != nil {
+		return nil, service.Status(err)
+	}
+	segs, err := segments(r.rootPath, path, req.GetFilename())
+	if err != nil {
+		return nil, service.Status(err)
+	}
+
+	from, to := nanoTime(req.GetFrom()), nanoTime(req.GetTo())
+	resp := &pb.ReadResponse{Lines: make([]*pb.LogLine, 0)}
+	var skipped int64
+	for _, seg := range segs {
+		if !seg.overlaps(from, to) {
+			continue
+		}
+		err := seg.readLines(func(line string) bool {
+			if !inRange(line, from, to) {
+				return true
+			}
+			if skipped < req.GetOffset() {
+				skipped++
+				return true
+			}
+			if len(resp.Lines) == limit {
+				resp.More = true
+				return false
+			}
+			resp.Lines = append(resp.Lines, &pb.LogLine{
+				Filename: req.GetFilename(),
+				Path:     path,
+				Line:     line,
+			})
+			return true
+		})
+		if err != nil {
+			return nil, service.Status(err)
+		}
+		if resp.More {
+			break
+		}
+	}
+	resp.NextOffset = req.GetOffset() + int64(len(resp.Lines))
+	return resp, nil
+}
+
+// nanoTime converts nanoseconds since epoch to time,
+// keeping zero as the zero time.
+func nanoTime(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
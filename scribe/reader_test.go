@@ -0,0 +1,91 @@
+package scribe
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRead(t *testing.T) {
+	root := createSegments(t)
+	defer os.RemoveAll(root)
+	r := logReader{target: &target{rootPath: root, files: newFileCache(0, 0)}}
+	// the first rotated segment ends on 01/09 10:00 and the second on 02/09 10:00
+	sep1 := time.Date(2018, 9, 1, 5, 0, 0, 0, time.Local).UnixNano()
+	sep2 := time.Date(2018, 9, 2, 0, 0, 0, 0, time.Local).UnixNano()
+	// the lines of the json and text formats are selected by their own time
+	timed := []string{
+		`{"time":"2018-09-03T10:00:00Z","line":"a"}`,
+		"2018-09-03T11:00:00Z [INFO] - b",
+		"raw c",
+		`{"time":"2018-09-03T12:00:00Z","line":"d"}`,
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "timed.log"), []byte(strings.Join(timed, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("failed to create timed.log: %v", err)
+	}
+	at := func(hour int) int64 {
+		return time.Date(2018, 9, 3, hour, 30, 0, 0, time.UTC).UnixNano()
+	}
+	pick := func(i ...int) string {
+		l := make([]string, 0, len(i))
+		for _, n := range i {
+			l = append(l, timed[n])
+		}
+		return strings.Join(l, ",")
+	}
+
+	var tc = []struct {
+		name string
+		req  *pb.ReadRequest
+		exp  string
+		more bool
+		code codes.Code
+	}{
+		{"all", &pb.ReadRequest{Filename: "app"}, "1,2,3,4,5", false, codes.OK},
+		{"first page", &pb.ReadRequest{Filename: "app", Limit: 3}, "1,2,3", true, codes.OK},
+		{"second page", &pb.ReadRequest{Filename: "app", Offset: 3, Limit: 3}, "4,5", false, codes.OK},
+		{"exact page", &pb.ReadRequest{Filename: "app", Offset: 2, Limit: 3}, "3,4,5", false, codes.OK},
+		{"since", &pb.ReadRequest{Filename: "app", From: sep2}, "3,4,5", false, codes.OK},
+		{"until", &pb.ReadRequest{Filename: "app", To: sep1}, "1,2", false, codes.OK},
+		{"lines since", &pb.ReadRequest{Filename: "timed", From: at(10)}, pick(1, 2, 3), false, codes.OK},
+		{"lines until", &pb.ReadRequest{Filename: "timed", To: at(10)}, pick(0, 2), false, codes.OK},
+		{"lines between", &pb.ReadRequest{Filename: "timed", From: at(10), To: at(11), Offset: 1}, pick(2), false, codes.OK},
+		{"missing file", &pb.ReadRequest{Filename: "none"}, "", false, codes.OK},
+		{"no filename", &pb.ReadRequest{}, "", false, codes.InvalidArgument},
+		{"negative offset", &pb.ReadRequest{Filename: "app", Offset: -1}, "", false, codes.InvalidArgument},
+		{"outside the root", &pb.ReadRequest{Path: "../..", Filename: "app"}, "", false, codes.InvalidArgument},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := r.Read(context.Background(), tt.req)
+			if status.Code(err) != tt.code {
+				t.Fatalf("expected code %v and got '%v'", tt.code, err)
+			}
+			if err != nil {
+				return
+			}
+			lines := make([]string, 0)
+			for _, l := range resp.GetLines() {
+				lines = append(lines, l.GetLine())
+			}
+			if got := strings.Join(lines, ","); got != tt.exp {
+				t.Errorf("expected lines %q and got %q", tt.exp, got)
+			}
+			if resp.GetMore() != tt.more {
+				t.Errorf("expected more to be %v and got %v", tt.more, resp.GetMore())
+			}
+			if resp.GetNextOffset() != tt.req.GetOffset()+int64(len(lines)) {
+				t.Errorf("expected next offset %d and got %d",
+					tt.req.GetOffset()+int64(len(lines)), resp.GetNextOffset())
+			}
+		})
+	}
+}
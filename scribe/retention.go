@@ -0,0 +1,240 @@
+package scribe
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	p "github.com/RomanosTrechlis/go-scribe/internal/util/format/print"
+)
+
+const (
+	// defaultRetentionInterval is the time between retention runs
+	defaultRetentionInterval = time.Minute
+	// maxDeletions is the number of deletions kept for reporting
+	maxDeletions = 1000
+)
+
+// The reasons a segment gets deleted for
+const (
+	reasonAge   = "age"
+	reasonCount = "count"
+	reasonBytes = "bytes"
+)
+
+// RetentionPolicy defines which rotated segments get deleted. The current
+// segment of a file is never deleted and zero values disable their limit.
+type RetentionPolicy struct {
+	// MaxAge deletes the segments rotated longer ago
+	MaxAge time.Duration
+	// MaxSegments keeps the newest rotated segments of every file
+	MaxSegments int
+	// MaxBytes deletes the oldest segments of a path while
+	// the files of the path, current ones included, exceed it
+	MaxBytes int64
+	// Interval is the time between runs, a minute when zero
+	Interval time.Duration
+	// DryRun reports the segments without deleting them
+	DryRun bool
+}
+
+func (rp RetentionPolicy) enabled() bool {
+	return rp.MaxAge > 0 || rp.MaxSegments > 0 || rp.MaxBytes > 0
+}
+
+// retention enforces the policy on the files under the root path
+// and keeps the latest deletions for reporting. In dry run mode
+// only the deletions of the latest run are kept, as the same
+// segments would be reported by every run.
+type retention struct {
+	// id of the scribe, reported with the deletions
+	id       string
+	policy   RetentionPolicy
+	rootPath string
+
+	// running serializes the runs of the ticker and the admin API
+	running sync.Mutex
+
+	mu        sync.Mutex
+	deletions []*pb.RetentionResponse_Deletion
+}
+
+// candidate is a rotated segment that may get deleted
+type candidate struct {
+	file logicalFile
+	seg  segment
+	size int64
+}
+
+// start runs the policy every interval until stop is closed
+func (r *retention) start(stop chan struct{}) {
+	if !r.policy.enabled() {
+		return
+	}
+	interval := r.policy.Interval
+	if interval <= 0 {
+		interval = defaultRetentionInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.run(time.Now()); err != nil {
+			p.Print(fmt.Sprintf("retention failed: %v", err))
+		}
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// run deletes, or in dry run mode reports, the segments
+// exceeding the policy at now and returns them.
+func (r *retention) run(now time.Time) ([]*pb.RetentionResponse_Deletion, error) {
+	if !r.policy.enabled() {
+		return nil, nil
+	}
+	r.running.Lock()
+	defer r.running.Unlock()
+	files, err := logicalFiles(r.rootPath, "")
+	if err != nil {
+		return nil, err
+	}
+	byPath := make(map[string][]logicalFile)
+	paths := make([]string, 0)
+	for _, f := range files {
+		if _, ok := byPath[f.path]; !ok {
+			paths = append(paths, f.path)
+		}
+		byPath[f.path] = append(byPath[f.path], f)
+	}
+
+	deleted := make([]*pb.RetentionResponse_Deletion, 0)
+	var failed error
+	for _, path := range paths {
+		d, err := r.enforce(byPath[path], now)
+		deleted = append(deleted, d...)
+		if err != nil {
+			failed = err
+		}
+	}
+
+	var size int64
+	for _, d := range deleted {
+		size += d.Size
+	}
+	if len(deleted) > 0 && !r.policy.DryRun {
+		p.Print(fmt.Sprintf("Retention deleted %d segments of %d bytes", len(deleted), size))
+	}
+	r.record(deleted)
+	return deleted, failed
+}
+
+// enforce applies the policy to the files of a path
+func (r *retention) enforce(files []logicalFile, now time.Time) ([]*pb.RetentionResponse_Deletion, error) {
+	deleted := make([]*pb.RetentionResponse_Deletion, 0)
+	var failed error
+	var total int64
+	remove := func(c candidate, reason string) {
+		if err := r.remove(c); err != nil {
+			failed = err
+			return
+		}
+		total -= c.size
+		deleted = append(deleted, &pb.RetentionResponse_Deletion{
+			Scribe:   r.id,
+			Path:     c.file.path,
+			Filename: c.file.filename,
+			Segment:  filepath.Base(c.seg.file),
+			Size:     c.size,
+			Reason:   reason,
+			Time:     now.UnixNano(),
+			DryRun:   r.policy.DryRun,
+		})
+	}
+
+	kept := make([]candidate, 0)
+	for _, f := range files {
+		segs, err := segments(r.rootPath, f.path, f.filename)
+		if err != nil {
+			return deleted, err
+		}
+		rotated := make([]candidate, 0, len(segs))
+		for _, s := range segs {
+			info, err := os.Stat(s.file)
+			if err != nil {
+				continue
+			}
+			total += info.Size()
+			if filepath.Base(s.file) == f.filename+".log" {
+				continue
+			}
+			rotated = append(rotated, candidate{file: f, seg: s, size: info.Size()})
+		}
+
+		for i, c := range rotated {
+			switch {
+			case r.policy.MaxAge > 0 && now.Sub(c.seg.end) > r.policy.MaxAge:
+				remove(c, reasonAge)
+			case r.policy.MaxSegments > 0 && i < len(rotated)-r.policy.MaxSegments:
+				remove(c, reasonCount)
+			default:
+				kept = append(kept, c)
+			}
+		}
+	}
+
+	if r.policy.MaxBytes <= 0 {
+		return deleted, failed
+	}
+	// the oldest segments of the path go first
+	sort.SliceStable(kept, func(i, j int) bool {
+		return kept[i].seg.end.Before(kept[j].seg.end)
+	})
+	for _, c := range kept {
+		if total <= r.policy.MaxBytes {
+			break
+		}
+		remove(c, reasonBytes)
+	}
+	return deleted, failed
+}
+
+// remove deletes the segment unless in dry run mode
+func (r *retention) remove(c candidate) error {
+	if r.policy.DryRun {
+		return nil
+	}
+	if err := os.Remove(c.seg.file); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("couldn't delete segment '%s': %v", c.seg.file, err)
+	}
+	return nil
+}
+
+// record keeps the deletions for reporting
+func (r *retention) record(deleted []*pb.RetentionResponse_Deletion) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.policy.DryRun {
+		r.deletions = deleted
+		return
+	}
+	r.deletions = append(r.deletions, deleted...)
+	if len(r.deletions) > maxDeletions {
+		r.deletions = r.deletions[len(r.deletions)-maxDeletions:]
+	}
+}
+
+// report returns the latest deletions, oldest first
+func (r *retention) report() []*pb.RetentionResponse_Deletion {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	report := make([]*pb.RetentionResponse_Deletion, len(r.deletions))
+	copy(report, r.deletions)
+	return report
+}
@@ -0,0 +1,124 @@
+package scribe
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRetention(t *testing.T) {
+	now := time.Date(2018, 9, 10, 12, 0, 0, 0, time.Local)
+	name := func(filename string, days int) string {
+		return filename + "_" + now.AddDate(0, 0, -days).Format(layout) + ".log"
+	}
+	// every file holds 10 bytes
+	files := []string{
+		"a.log", name("a", 3), name("a", 2), name("a", 1),
+		"p/b.log", "p/" + name("b", 5), "p/" + name("b", 4),
+	}
+
+	var tc = []struct {
+		name    string
+		policy  RetentionPolicy
+		deleted map[string]string
+	}{
+		{"disabled", RetentionPolicy{}, map[string]string{}},
+		{"age", RetentionPolicy{MaxAge: 60 * time.Hour},
+			map[string]string{name("a", 3): reasonAge, "p/" + name("b", 5): reasonAge, "p/" + name("b", 4): reasonAge}},
+		{"count", RetentionPolicy{MaxSegments: 1},
+			map[string]string{name("a", 3): reasonCount, name("a", 2): reasonCount, "p/" + name("b", 5): reasonCount}},
+		// the paths hold 40 and 30 bytes, and the current files are never deleted
+		{"bytes", RetentionPolicy{MaxBytes: 25},
+			map[string]string{name("a", 3): reasonBytes, name("a", 2): reasonBytes, "p/" + name("b", 5): reasonBytes}},
+		{"bytes below current files", RetentionPolicy{MaxBytes: 5},
+			map[string]string{name("a", 3): reasonBytes, name("a", 2): reasonBytes, name("a", 1): reasonBytes,
+				"p/" + name("b", 5): reasonBytes, "p/" + name("b", 4): reasonBytes}},
+		{"combined", RetentionPolicy{MaxSegments: 2, MaxBytes: 25},
+			map[string]string{name("a", 3): reasonCount, name("a", 2): reasonBytes, "p/" + name("b", 5): reasonBytes}},
+		{"dry run", RetentionPolicy{MaxAge: 60 * time.Hour, DryRun: true},
+			map[string]string{name("a", 3): reasonAge, "p/" + name("b", 5): reasonAge, "p/" + name("b", 4): reasonAge}},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := ioutil.TempDir("", "retention")
+			if err != nil {
+				t.Fatalf("failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(root)
+			for _, f := range files {
+				os.MkdirAll(filepath.Join(root, filepath.Dir(f)), os.ModePerm)
+				ioutil.WriteFile(filepath.Join(root, f), []byte("123456789\n"), 0644)
+			}
+
+			r := &retention{id: "s1", rootPath: root, policy: tt.policy}
+			deleted, err := r.run(now)
+			if err != nil {
+				t.Fatalf("expected nil error and got '%v'", err)
+			}
+			got := make(map[string]string)
+			for _, d := range deleted {
+				got[filepath.Join(d.Path, d.Segment)] = d.Reason
+				if d.Scribe != "s1" || d.Size != 10 || d.DryRun != tt.policy.DryRun {
+					t.Errorf("unexpected deletion %v", d)
+				}
+			}
+			if !equalMaps(got, tt.deleted) {
+				t.Errorf("expected deletions %v and got %v", tt.deleted, got)
+			}
+			if len(r.report()) != len(deleted) {
+				t.Errorf("expected %d reported deletions and got %d", len(deleted), len(r.report()))
+			}
+
+			for _, f := range files {
+				_, err := os.Stat(filepath.Join(root, f))
+				removed := tt.deleted[f] != "" && !tt.policy.DryRun
+				if removed != os.IsNotExist(err) {
+					t.Errorf("expected %s to be removed %v", f, removed)
+				}
+			}
+		})
+	}
+}
+
+func TestRetentionReport(t *testing.T) {
+	root, err := ioutil.TempDir("", "retention")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	now := time.Now()
+	r := &retention{rootPath: root, policy: RetentionPolicy{MaxSegments: 1}}
+	for i := 0; i < 3; i++ {
+		for _, days := range []int{2, 1} {
+			ioutil.WriteFile(filepath.Join(root, "f_"+now.AddDate(0, 0, -days).Format(layout)+".log"), nil, 0644)
+		}
+		if _, err := r.run(now); err != nil {
+			t.Fatalf("expected nil error and got '%v'", err)
+		}
+	}
+	// the deletions of every run are reported
+	report := r.report()
+	if len(report) != 3 {
+		t.Fatalf("expected 3 deletions and got %d", len(report))
+	}
+	for _, d := range report {
+		if d.Filename != "f" || d.Reason != reasonCount {
+			t.Errorf("expected a segment of f deleted for count and got %v", d)
+		}
+	}
+}
+
+func equalMaps(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package scribe
+
+import (
+	"fmt"
+	"time"
+)
+
+// Rotation policies, besides custom durations like 6h
+const (
+	// RotateNone rotates the files only on size
+	RotateNone = "none"
+	// RotateHourly rotates the files at the start of every hour
+	RotateHourly = "hourly"
+	// RotateDaily rotates the files at midnight
+	RotateDaily = "daily"
+)
+
+const day = 24 * time.Hour
+
+// rotation defines the time boundaries the files get rotated at, in its location.
+// A file holds only the lines written during one period and is rotated by the
+// first write after the period ends, so that files not written don't rotate.
+// The rotated segment is named after the end of its period.
+type rotation struct {
+	// every is the length of a period, zero disables the rotation
+	every time.Duration
+	loc   *time.Location
+}
+
+// parseRotation parses the rotation policy, none, hourly, daily or a duration,
+// in the timezone, i.e. UTC or Europe/Athens, which is the local one when empty.
+// Periods shorter than a day start at midnight and must divide the day,
+// while longer ones must consist of whole days and start at the epoch.
+func parseRotation(policy, timezone string) (rotation, error) {
+	loc := time.Local
+	if timezone != "" {
+		l, err := time.LoadLocation(timezone)
+		if err != nil {
+			return rotation{}, fmt.Errorf("unknown timezone '%s': %v", timezone, err)
+		}
+		loc = l
+	}
+
+	r := rotation{loc: loc}
+	switch policy {
+	case "", RotateNone:
+	case RotateHourly:
+		r.every = time.Hour
+	case RotateDaily:
+		r.every = day
+	default:
+		d, err := time.ParseDuration(policy)
+		if err != nil {
+			return rotation{}, fmt.Errorf("unknown rotation '%s'", policy)
+		}
+		if d < time.Minute || d%time.Second != 0 || (d < day && day%d != 0) || (d > day && d%day != 0) {
+			return rotation{}, fmt.Errorf("rotation '%s' must divide the day or consist of whole days", policy)
+		}
+		r.every = d
+	}
+	return r, nil
+}
+
+// enabled checks if the files rotate on time
+func (r rotation) enabled() bool {
+	return r.every > 0
+}
+
+// start returns the start of the period t belongs to. The periods follow
+// the wall clock, so the periods of the days daylight saving time
+// changes may be shorter or longer than the rest.
+func (r rotation) start(t time.Time) time.Time {
+	t = t.In(r.loc)
+	if r.every <= day {
+		every := int(r.every / time.Second)
+		secs := t.Hour()*3600 + t.Minute()*60 + t.Second()
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, secs/every*every, 0, r.loc)
+	}
+	days := int(r.every / day)
+	// the days since the epoch are counted on the calendar
+	n := int(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400)
+	return time.Date(t.Year(), t.Month(), t.Day()-n%days, 0, 0, 0, 0, r.loc)
+}
+
+// end returns the end of the period starting at start
+func (r rotation) end(start time.Time) time.Time {
+	start = start.In(r.loc)
+	if r.every > day {
+		return time.Date(start.Year(), start.Month(), start.Day()+int(r.every/day), 0, 0, 0, 0, r.loc)
+	}
+	secs := start.Hour()*3600 + start.Minute()*60 + start.Second() + int(r.every/time.Second)
+	return time.Date(start.Year(), start.Month(), start.Day(), 0, 0, secs, 0, r.loc)
+}
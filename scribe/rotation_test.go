@@ -0,0 +1,119 @@
+package scribe
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+)
+
+func TestParseRotation(t *testing.T) {
+	var tc = []struct {
+		policy   string
+		timezone string
+		every    time.Duration
+		err      bool
+	}{
+		{"", "", 0, false},
+		{RotateNone, "UTC", 0, false},
+		{RotateHourly, "", time.Hour, false},
+		{RotateDaily, "Europe/Athens", day, false},
+		{"6h", "", 6 * time.Hour, false},
+		{"168h", "", 7 * day, false},
+		{"7h", "", 0, true},
+		{"36h", "", 0, true},
+		{"30s", "", 0, true},
+		{"often", "", 0, true},
+		{RotateDaily, "Nowhere/City", 0, true},
+	}
+	for _, tt := range tc {
+		r, err := parseRotation(tt.policy, tt.timezone)
+		if tt.err && err == nil {
+			t.Errorf("expected error for '%s' in '%s' and got nil", tt.policy, tt.timezone)
+		}
+		if !tt.err && err != nil {
+			t.Errorf("expected nil error for '%s' in '%s' and got '%v'", tt.policy, tt.timezone, err)
+		}
+		if r.every != tt.every {
+			t.Errorf("expected period %v for '%s' and got %v", tt.every, tt.policy, r.every)
+		}
+	}
+}
+
+func TestRotationPeriods(t *testing.T) {
+	athens, err := time.LoadLocation("Europe/Athens")
+	if err != nil {
+		t.Skipf("timezone data is missing: %v", err)
+	}
+
+	var tc = []struct {
+		name  string
+		every time.Duration
+		loc   *time.Location
+		t     time.Time
+		start time.Time
+		end   time.Time
+	}{
+		{"hourly", time.Hour, time.UTC, time.Date(2018, 9, 1, 10, 42, 7, 0, time.UTC),
+			time.Date(2018, 9, 1, 10, 0, 0, 0, time.UTC), time.Date(2018, 9, 1, 11, 0, 0, 0, time.UTC)},
+		{"daily in timezone", day, athens, time.Date(2018, 9, 1, 22, 30, 0, 0, time.UTC),
+			time.Date(2018, 9, 2, 0, 0, 0, 0, athens), time.Date(2018, 9, 3, 0, 0, 0, 0, athens)},
+		{"six hours", 6 * time.Hour, time.UTC, time.Date(2018, 9, 1, 23, 59, 59, 0, time.UTC),
+			time.Date(2018, 9, 1, 18, 0, 0, 0, time.UTC), time.Date(2018, 9, 2, 0, 0, 0, 0, time.UTC)},
+		// the epoch was a Thursday
+		{"weekly", 7 * day, time.UTC, time.Date(2018, 9, 1, 12, 0, 0, 0, time.UTC),
+			time.Date(2018, 8, 30, 0, 0, 0, 0, time.UTC), time.Date(2018, 9, 6, 0, 0, 0, 0, time.UTC)},
+		// the clocks went back an hour at 04:00 on 28 October 2018 in Athens
+		{"daylight saving day", day, athens, time.Date(2018, 10, 28, 12, 0, 0, 0, athens),
+			time.Date(2018, 10, 28, 0, 0, 0, 0, athens), time.Date(2018, 10, 29, 0, 0, 0, 0, athens)},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rotation{every: tt.every, loc: tt.loc}
+			start := r.start(tt.t)
+			if !start.Equal(tt.start) {
+				t.Errorf("expected start %v and got %v", tt.start, start)
+			}
+			if end := r.end(start); !end.Equal(tt.end) {
+				t.Errorf("expected end %v and got %v", tt.end, end)
+			}
+		})
+	}
+}
+
+func TestFileCacheTimeRotation(t *testing.T) {
+	root, err := ioutil.TempDir("", "rotation")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	c := newFileCache(1, 0)
+	defer c.closeAll()
+	c.rotation = rotation{every: time.Hour, loc: time.Local}
+	if err := c.write(root, "", "t", []string{"old"}, -1, pb.Ack_ACK_RECEIVED, nil); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	// the file was written during the previous hour
+	of := c.files["t"].Value.(*openFile)
+	of.period = of.period.Add(-time.Hour)
+	if err := c.write(root, "", "t", []string{"new"}, -1, pb.Ack_ACK_RECEIVED, nil); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+
+	// the rotated segment is named after the end of its period
+	rotated := filepath.Join(root, segmentName("t", c.rotation.end(of.period), 0))
+	for file, exp := range map[string]string{rotated: "old\n", filepath.Join(root, "t.log"): "new\n"} {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Errorf("failed to read %s: %v", file, err)
+			continue
+		}
+		if string(b) != exp {
+			t.Errorf("expected %q in %s and got %q", exp, file, string(b))
+		}
+	}
+}
@@ -2,6 +2,8 @@ package scribe
 
 import (
 	"fmt"
+	"os"
+	"runtime"
 	"sync"
 	"time"
 
@@ -10,9 +12,13 @@ import (
 	"github.com/RomanosTrechlis/go-scribe/internal/util/gserver"
 	"github.com/RomanosTrechlis/go-scribe/mediator"
 	"github.com/RomanosTrechlis/go-scribe/service"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
 const (
+	// layout is the local timestamp of the legacy names of rotated segments
 	layout string = "02012006150405"
 )
 
@@ -26,11 +32,35 @@ type LogScribe struct {
 	// GRPC server
 	gRPC gserver.GRPC
 
-	// input stream of protobuf requests
-	stream chan pb.LogRequest
+	// input stream of protobuf request batches
+	stream chan service.Entry
+	// workers write the batches of the stream, every one
+	// the files of its shard, queuing up to queueDepth parts
+	workers    int
+	queueDepth int
+	// handling is done once the workers wrote the queued parts after stopping
+	handling sync.WaitGroup
+	// onError handles the failed writes and errStats counts them
+	onError  ErrorPolicy
+	errStats ErrorStats
+	// ack is the default acknowledgement mode of requests
+	ack pb.Ack
 
 	// mediator is the address of the mediator middleware
 	mediator string
+	// health reports the serving status of the services
+	health *service.Health
+	// retention deletes the rotated segments exceeding its policy
+	retention *retention
+	// disk degrades the scribe while its free space is low
+	disk *diskMonitor
+	// spool records the accepted batches until written, when not nil
+	spool *spool
+	// replay holds the spool segments left from the previous run
+	replay []string
+	// writeFailed is set while the writes fail for lack of space
+	writeFailed bool
+	healthMu    sync.Mutex
 
 	// counter counts the requests handled by LogScribe
 	counter   int64
@@ -38,8 +68,184 @@ type LogScribe struct {
 	stopAll   chan struct{}
 }
 
+// Option configures optional behaviour of a LogScribe
+type Option func(s *LogScribe) error
+
+// WithFormat sets the format, raw, json or text, of the persisted lines.
+// The layout is used only by the text format.
+func WithFormat(format, layout string) Option {
+	return func(s *LogScribe) error {
+		f, err := NewFormatter(format, layout)
+		if err != nil {
+			return err
+		}
+		s.formatter = f
+		return nil
+	}
+}
+
+// WithAck sets the default acknowledgement mode, received, written or synced,
+// for requests that don't ask for a specific one.
+func WithAck(mode string) Option {
+	return func(s *LogScribe) error {
+		ack, err := ParseAck(mode)
+		if err != nil {
+			return err
+		}
+		s.ack = ack
+		return nil
+	}
+}
+
+// WithRotation rotates the files on time boundaries besides size. The policy is
+// none, hourly, daily or a duration, i.e. 6h, and the timezone of the boundaries,
+// i.e. UTC or Europe/Athens, is the local one when empty. Durations shorter than a
+// day must divide it, as the periods start at midnight, and longer ones must
+// consist of whole days.
+func WithRotation(policy, timezone string) Option {
+	return func(s *LogScribe) error {
+		r, err := parseRotation(policy, timezone)
+		if err != nil {
+			return err
+		}
+		s.files.rotation = r
+		return nil
+	}
+}
+
+// WithRetention deletes, every interval of the policy,
+// the rotated segments exceeding the limits of the policy.
+func WithRetention(policy RetentionPolicy) Option {
+	return func(s *LogScribe) error {
+		if policy.MaxAge < 0 || policy.MaxSegments < 0 || policy.MaxBytes < 0 || policy.Interval < 0 {
+			return fmt.Errorf("invalid retention policy %+v", policy)
+		}
+		s.retention.policy = policy
+		return nil
+	}
+}
+
+// WithSegmentCompression compresses the rotated segments with the codec,
+// gzip or none, in the background. The compressed segments keep being
+// listed, read and searched like the uncompressed ones.
+func WithSegmentCompression(codec string) Option {
+	return func(s *LogScribe) error {
+		c, err := newCompressor(codec, s.rootPath)
+		if err != nil {
+			return err
+		}
+		if c != nil {
+			c.retention = &s.retention.running
+		}
+		s.files.compressor = c
+		return nil
+	}
+}
+
+// WithWatermarks degrades the scribe while the free space of its log root
+// is below the low watermark, rejecting the requests with severity lower
+// than the one kept, until the free space rises to the high watermark.
+// A zero high watermark is the same as the low one.
+func WithWatermarks(w Watermarks) Option {
+	return func(s *LogScribe) error {
+		if w.High == 0 {
+			w.High = w.Low
+		}
+		if w.Low < 0 || w.High < w.Low || w.Interval < 0 {
+			return fmt.Errorf("invalid watermarks %+v", w)
+		}
+		s.disk.marks = w
+		return nil
+	}
+}
+
+// WithSpool records every accepted batch in a write-ahead log under dir
+// before acknowledging it, until its lines are written to their files,
+// or handled by the error policy, so that the batches in flight survive a crash or a shutdown. The
+// batches left in the spool are written when the scribe starts serving.
+// A batch is synced to the spool when synced to its files, and stays in
+// the spool until its lines buffered by the flush policy get flushed.
+func WithSpool(dir string) Option {
+	return func(s *LogScribe) error {
+		if dir == "" {
+			return nil
+		}
+		sp, files, err := openSpool(dir)
+		if err != nil {
+			return err
+		}
+		s.spool, s.replay = sp, files
+		return nil
+	}
+}
+
+// WithWorkers sets the number of workers writing the requests and the number
+// of parts of batches queued for every worker. The files are divided among the
+// workers, so that different files get written in parallel while the requests
+// of a file are written in order. Zero workers are as many as the CPUs and
+// zero depth keeps the default, 64.
+func WithWorkers(workers, depth int) Option {
+	return func(s *LogScribe) error {
+		if workers < 0 || depth < 0 {
+			return fmt.Errorf("invalid workers %d and queue depth %d", workers, depth)
+		}
+		if workers > 0 {
+			s.workers = workers
+		}
+		if depth > 0 {
+			s.queueDepth = depth
+		}
+		return nil
+	}
+}
+
+// WithErrorPolicy sets what happens to the requests failed to be written:
+// skip drops them, retry retries the writes with backoff before dropping
+// them and deadletter writes them to the dead-letter file instead.
+func WithErrorPolicy(policy ErrorPolicy) Option {
+	return func(s *LogScribe) error {
+		e, err := policy.validate()
+		if err != nil {
+			return err
+		}
+		s.onError = e
+		return nil
+	}
+}
+
+// WithOpenFiles sets the number of files kept open for writing and the time
+// a file stays open without writes. Zero max closes every file after writing.
+func WithOpenFiles(max int, idle time.Duration) Option {
+	return func(s *LogScribe) error {
+		if max < 0 || idle < 0 {
+			return fmt.Errorf("invalid open files %d and idle timeout %v", max, idle)
+		}
+		s.files.max, s.files.idle = max, idle
+		return nil
+	}
+}
+
+// WithFlush sets the flush policy, line, size or interval, of the written
+// lines. Unless the policy is line, the lines of every open file are buffered
+// in a buffer of bufferSize bytes, which is written to the file when full,
+// or every interval for the interval policy. Zero bufferSize and interval
+// keep their defaults, 64KB and a second.
+func WithFlush(policy string, bufferSize int64, interval time.Duration) Option {
+	return func(s *LogScribe) error {
+		return s.files.policy.setFlush(policy, bufferSize, interval)
+	}
+}
+
+// WithSync sets the sync policy, never, interval or always, of the written lines.
+// The interval policy syncs the open files every interval, a second when zero.
+func WithSync(policy string, interval time.Duration) Option {
+	return func(s *LogScribe) error {
+		return s.files.policy.setSync(policy, interval)
+	}
+}
+
 // New creates a Scribe struct
-func New(id, root string, port int, fileSize int64, mediator, crt, key, ca string) (*LogScribe, error) {
+func New(id, root string, port int, fileSize int64, mediator, crt, key, ca string, opts ...Option) (*LogScribe, error) {
 	srv, err := gserver.New(crt, key, ca)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create grpc server: %v", err)
@@ -50,7 +256,7 @@ func New(id, root string, port int, fileSize int64, mediator, crt, key, ca strin
 		return nil, err
 	}
 
-	return &LogScribe{
+	s := &LogScribe{
 		id:     id,
 		target: *t,
 		gRPC: gserver.GRPC{
@@ -58,9 +264,22 @@ func New(id, root string, port int, fileSize int64, mediator, crt, key, ca strin
 			Port:   port,
 			Stop:   make(chan struct{}),
 		},
-		stream:   make(chan pb.LogRequest),
-		mediator: mediator,
-	}, nil
+		stream:     make(chan service.Entry),
+		workers:    runtime.NumCPU(),
+		queueDepth: defaultQueueDepth,
+		mediator:   mediator,
+		health:     service.NewHealth(service.LogScribeService, service.LogReaderService),
+	}
+	s.onError, _ = ErrorPolicy{}.validate()
+	s.retention = &retention{id: id, rootPath: s.rootPath}
+	s.disk = &diskMonitor{rootPath: s.rootPath}
+	s.disk.onChange = s.diskChanged
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
 }
 
 // Serve initializes log Scribe's servers
@@ -68,8 +287,16 @@ func (s *LogScribe) Serve() {
 	p.Print("Log Scribe is starting...")
 	s.stopAll = make(chan struct{})
 	s.startTime = time.Now()
+	s.replaySpool()
 	// go func listens to stream and stop channels
-	go s.serviceHandler(s.gRPC.Stop)
+	s.handling.Add(1)
+	go func() {
+		defer s.handling.Done()
+		s.serviceHandler(s.gRPC.Stop)
+	}()
+	go s.retention.start(s.gRPC.Stop)
+	go s.disk.start(s.gRPC.Stop)
+	go s.files.compressor.start(s.gRPC.Stop)
 
 	// rpc server
 	go gserver.Serve(s.register(), fmt.Sprintf(":%d", s.gRPC.Port), s.gRPC.Server)
@@ -82,7 +309,18 @@ func (s *LogScribe) Serve() {
 func (s *LogScribe) Shutdown() {
 	close(s.stopAll)
 	p.Print("Initializing shut down, please wait.")
+	s.health.Shutdown()
 	close(s.gRPC.Stop)
+	// the files and the spool stay open until the workers drained their queues
+	s.handling.Wait()
+	if err := s.files.closeAll(); err != nil {
+		p.Print(fmt.Sprintf("failed to flush files: %v", err))
+	}
+	if s.spool != nil {
+		if err := s.spool.close(); err != nil {
+			p.Print(fmt.Sprintf("failed to close spool: %v", err))
+		}
+	}
 	p.Print(fmt.Sprintf("Log Scribe handled %d requests during %v", s.counter, time.Since(s.startTime)))
 	p.Print("Log Scribe shut down")
 }
@@ -108,42 +346,175 @@ func (s *LogScribe) GetInfo() mediator.Info {
 	}
 }
 
-// serviceHandler implements the protobuf service
+// Logger returns the service pushing requests to the scribe's stream
+func (s *LogScribe) Logger() service.Logger {
+	// with a spool the batches are acknowledged once recorded
+	return service.Logger{Stream: s.stream, Stop: s.gRPC.Stop, Ack: s.ack, Admit: s.disk.admit, Confirm: s.spool != nil}
+}
+
+// accept records the batch taken from the stream in the spool, syncing it
+// when it will be synced to its files, and confirms it to the sender.
+// It returns the segment the batch was recorded to, zero when not spooled.
+func (s *LogScribe) accept(req service.Entry) (int64, error) {
+	if s.spool == nil {
+		return 0, nil
+	}
+	seg, err := s.spool.append(&req.Batch, req.Ack == pb.Ack_ACK_SYNCED || s.files.policy.sync == SyncAlways)
+	if req.Accepted != nil {
+		req.Accepted <- err
+	}
+	return seg, err
+}
+
+// replaySpool writes the batches left in the spool by the previous run.
+// A segment that fails is kept, along with the ones after it, to be
+// replayed when the scribe starts again.
+func (s *LogScribe) replaySpool() {
+	if len(s.replay) == 0 {
+		return
+	}
+	count := 0
+	for i, file := range s.replay {
+		n, err := replay(file, func(b *pb.LogBatch) error {
+			err := s.writeBatch(b.GetEntries(), pb.Ack_ACK_WRITTEN, nil)
+			if status.Code(err) == codes.InvalidArgument {
+				// it would fail every time
+				p.Print(fmt.Sprintf("skipping spooled batch: %v", err))
+				return nil
+			}
+			return err
+		})
+		count += n
+		if err != nil {
+			p.Print(fmt.Sprintf("failed to replay spool, %d segments kept: %v", len(s.replay)-i, err))
+			break
+		}
+		if err := os.Remove(file); err != nil {
+			p.Print(fmt.Sprintf("failed to delete replayed spool segment: %v", err))
+		}
+	}
+	s.replay = nil
+	p.Print(fmt.Sprintf("Replayed %d batches from the spool", count))
+}
+
+// Disk describes the free space of the log root as of the latest check
+func (s *LogScribe) Disk() DiskStats {
+	return s.disk.stats()
+}
+
+// ListFiles describes the logical files of the scribe
+// whose path is prefix or lies under it.
+func (s *LogScribe) ListFiles(prefix string) ([]*pb.ListFilesResponse_File, error) {
+	if err := s.files.flushAll(); err != nil {
+		return nil, err
+	}
+	files, err := listFiles(s.rootPath, prefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		f.Scribe = s.id
+	}
+	return files, nil
+}
+
+// Retention reports the latest segments deleted by the retention policy,
+// enforcing the policy first when run is true.
+func (s *LogScribe) Retention(run bool) ([]*pb.RetentionResponse_Deletion, error) {
+	if run {
+		if _, err := s.retention.run(time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	return s.retention.report(), nil
+}
+
+// serviceHandler implements the protobuf service, dispatching
+// the batches of the stream to the workers of their files. Once stop is
+// closed, it closes the queues and returns when the workers drained them.
 func (s *LogScribe) serviceHandler(stop chan struct{}) {
+	var workers sync.WaitGroup
+	queues := make([]chan part, s.workers)
+	for i := range queues {
+		queues[i] = make(chan part, s.queueDepth)
+		workers.Add(1)
+		go func(queue chan part) {
+			defer workers.Done()
+			s.worker(queue, stop)
+		}(queues[i])
+	}
 	for {
 		select {
 		case req := <-s.stream:
-			s.counter++
-			err := s.handleIncomingRequest(req)
+			spooled, err := s.accept(req)
 			if err != nil {
-				fmt.Printf("hanldeIncomingRequest returned with error: %v", err)
-				return
+				// the sender gets the error
+				p.Print(fmt.Sprintf("failed to spool %d requests: %v", len(req.Batch.GetEntries()), err))
+				continue
 			}
+			s.counter += int64(len(req.Batch.GetEntries()))
+			s.dispatch(queues, req, spooled, stop)
 		case <-stop:
+			for _, queue := range queues {
+				close(queue)
+			}
+			workers.Wait()
 			p.Print("serviceHandler stopped")
 			return
 		}
 	}
 }
 
+// complete reports the result of writing the batch, whose failed
+// requests, if any, have been handled by the error policy.
+func (s *LogScribe) complete(req service.Entry, err error) {
+	if err != nil {
+		// the client gets the status of the failure, i.e. ResourceExhausted
+		st := status.Convert(service.Status(err)).Proto()
+		st.Message = "failed to write batch: " + st.Message
+		err = status.ErrorProto(st)
+	}
+	s.reportHealth(err)
+	if status.Code(service.Status(err)) == codes.ResourceExhausted && s.disk.enabled() {
+		// degrade without waiting for the next check
+		s.disk.check()
+	}
+	if req.Done != nil {
+		// the client waits for the result and gets informed of the error
+		req.Done <- err
+	}
+}
+
 func (s *LogScribe) register() func() {
 	return func() {
-		log := service.Logger{Stream: s.stream}
-		pb.RegisterLogScribeServer(s.gRPC.Server, log)
+		pb.RegisterLogScribeServer(s.gRPC.Server, s.Logger())
+		pb.RegisterLogReaderServer(s.gRPC.Server, logReader{target: &s.target, id: s.id, stop: s.gRPC.Stop})
 
-		if s.mediator != "" {
-			pinger := &service.Pinger{}
-			pb.RegisterPingerServer(s.gRPC.Server, pinger)
-		}
+		healthpb.RegisterHealthServer(s.gRPC.Server, s.health)
+	}
+}
+
+// reportHealth sets the scribe as not serving requests while the disk
+// is full or degraded, and as serving again once a write succeeds,
+// or the free space rises above the watermarks, while not degraded.
+func (s *LogScribe) reportHealth(err error) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	if err == nil {
+		s.writeFailed = false
+	} else if status.Code(service.Status(err)) == codes.ResourceExhausted {
+		s.writeFailed = true
 	}
+	s.health.SetServing(service.LogScribeService, !s.writeFailed && !s.disk.isDegraded())
 }
 
-func (s *LogScribe) handleIncomingRequest(r pb.LogRequest) error {
-	var mu sync.RWMutex
-	mu.Lock()
-	defer mu.Unlock()
-	if err := writeLine(s.rootPath, r.Path, r.Filename, r.Line, s.fileSize); err != nil {
-		return fmt.Errorf("failed to write line: %v", err)
+// diskChanged reports the health when the scribe degrades or recovers
+func (s *LogScribe) diskChanged(degraded bool) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	if !degraded {
+		// the space got reclaimed, so the writes may succeed again
+		s.writeFailed = false
 	}
-	return nil
+	s.health.SetServing(service.LogScribeService, !s.writeFailed && !degraded)
 }
@@ -0,0 +1,194 @@
+package scribe
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	"github.com/RomanosTrechlis/go-scribe/service"
+)
+
+const (
+	// defaultSearchLimit is the number of results
+	// returned by Search when no limit is given.
+	defaultSearchLimit = 1000
+	// maxSearchLimit is the maximum number of results returned by Search
+	maxSearchLimit = 100000
+)
+
+// logicalFile is a file as the clients know it,
+// regardless of the segments it is stored in.
+type logicalFile struct {
+	// path is relative to the root path
+	path     string
+	filename string
+}
+
+// logicalFiles lists the logical files under the root path
+// whose path is prefix or lies under it, sorted by path and filename.
+func logicalFiles(rootPath, prefix string) ([]logicalFile, error) {
+	prefix = filepath.Clean("/" + prefix)
+	files := make(map[logicalFile]bool)
+	err := filepath.Walk(rootPath, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(rootPath, filepath.Dir(file))
+		if err != nil {
+			return err
+		}
+		dir := filepath.Clean("/" + rel)
+		if info.IsDir() {
+			// skip the directories no request can write to, i.e. the dead letters
+			if file != rootPath && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			// skip the directories that can hold neither the prefix nor files under it
+			p := filepath.Clean("/" + filepath.Join(rel, info.Name()))
+			if file != rootPath && !under(p, prefix) && !under(prefix, p) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !under(dir, prefix) {
+			return nil
+		}
+		name, ok := logicalName(info.Name())
+		if !ok {
+			return nil
+		}
+		files[logicalFile{path: strings.TrimPrefix(dir, "/"), filename: name}] = true
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't list files under '%s': %w", rootPath, err)
+	}
+
+	list := make([]logicalFile, 0, len(files))
+	for f := range files {
+		list = append(list, f)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].path != list[j].path {
+			return list[i].path < list[j].path
+		}
+		return list[i].filename < list[j].filename
+	})
+	return list, nil
+}
+
+// under checks if the slash rooted path is dir or lies under it
+func under(path, dir string) bool {
+	return dir == "/" || path == dir || strings.HasPrefix(path, dir+"/")
+}
+
+// logicalName returns the filename of the logical file a segment belongs to
+func logicalName(name string) (string, bool) {
+	compressed := strings.HasSuffix(name, gzipExt)
+	if !strings.HasSuffix(strings.TrimSuffix(name, gzipExt), ".log") {
+		return "", false
+	}
+	base := strings.TrimSuffix(strings.TrimSuffix(name, gzipExt), ".log")
+	i := strings.LastIndex(base, "_")
+	if i > 0 {
+		if _, _, ok := rotationTime(name, base[:i]); ok {
+			return base[:i], true
+		}
+	}
+	// only the rotated segments get compressed
+	return base, base != "" && !compressed
+}
+
+// matcher returns a function matching the lines against the query
+func matcher(query string, regex bool) (func(string) bool, error) {
+	if query == "" {
+		return nil, service.InvalidArgument("query", "must not be empty")
+	}
+	if !regex {
+		return func(line string) bool {
+			return strings.Contains(line, query)
+		}, nil
+	}
+	re, err := regexp.Compile(query)
+	if err != nil {
+		return nil, service.InvalidArgument("query", err.Error())
+	}
+	return re.MatchString, nil
+}
+
+// Search implements the Search protobuf service over the files of the scribe
+func (r logReader) Search(req *pb.SearchRequest, stream pb.LogReader_SearchServer) error {
+	match, err := matcher(req.GetQuery(), req.GetRegex())
+	if err != nil {
+		return err
+	}
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	if err := r.files.flushAll(); err != nil {
+		return service.Status(err)
+	}
+	files, err := logicalFiles(r.rootPath, req.GetPathPrefix())
+	if err != nil {
+		return service.Status(err)
+	}
+
+	ctx := stream.Context()
+	from, to := nanoTime(req.GetFrom()), nanoTime(req.GetTo())
+	sent := 0
+	for _, f := range files {
+		segs, err := segments(r.rootPath, f.path, f.filename)
+		if err != nil {
+			return service.Status(err)
+		}
+		for _, seg := range segs {
+			if !seg.overlaps(from, to) {
+				continue
+			}
+			var n int64
+			var sendErr error
+			err := seg.readLines(func(line string) bool {
+				n++
+				if !match(line) || !inRange(line, from, to) {
+					// a cancelled search stops even when nothing matches
+					return n%1024 != 0 || ctx.Err() == nil
+				}
+				sendErr = stream.Send(&pb.SearchResult{
+					Scribe:     r.id,
+					Filename:   f.filename,
+					Path:       f.path,
+					Segment:    filepath.Base(seg.file),
+					LineNumber: n,
+					Line:       line,
+				})
+				sent++
+				return sendErr == nil && sent < limit
+			})
+			if sendErr != nil {
+				return sendErr
+			}
+			if err != nil {
+				return service.Status(err)
+			}
+			if ctx.Err() != nil {
+				return service.Status(ctx.Err())
+			}
+			if sent == limit {
+				return nil
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package scribe
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// searchStream collects the results sent by Search
+type searchStream struct {
+	grpc.ServerStream
+	results []*pb.SearchResult
+}
+
+func (s *searchStream) Send(r *pb.SearchResult) error {
+	s.results = append(s.results, r)
+	return nil
+}
+
+func (s *searchStream) Context() context.Context {
+	return context.Background()
+}
+
+func TestLogicalFiles(t *testing.T) {
+	root := createSegments(t)
+	defer os.RemoveAll(root)
+	if err := os.MkdirAll(filepath.Join(root, "web", "api"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "web", "api", "access.log"), []byte("x\n"), 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	// the dead letters are left out
+	if err := os.MkdirAll(filepath.Join(root, DeadLetterPath), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, DeadLetterPath, DeadLetterFile+".log"), []byte("x\n"), 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	var tc = []struct {
+		prefix string
+		exp    []logicalFile
+	}{
+		{"", []logicalFile{{"", "app"}, {"", "app_notatime"}, {"", "app_x"}, {"web/api", "access"}}},
+		{"web", []logicalFile{{"web/api", "access"}}},
+		{"/web/api/", []logicalFile{{"web/api", "access"}}},
+		{"we", []logicalFile{}},
+	}
+	for _, tt := range tc {
+		files, err := logicalFiles(root, tt.prefix)
+		if err != nil {
+			t.Fatalf("expected nil error and got '%v'", err)
+		}
+		if !reflect.DeepEqual(files, tt.exp) {
+			t.Errorf("for prefix '%s' expected %v and got %v", tt.prefix, tt.exp, files)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	root := createSegments(t)
+	defer os.RemoveAll(root)
+	r := logReader{target: &target{rootPath: root, files: newFileCache(0, 0)}, id: "s1"}
+
+	var tc = []struct {
+		name string
+		req  *pb.SearchRequest
+		exp  string
+		code codes.Code
+	}{
+		{"substring", &pb.SearchRequest{Query: "4"}, "app_02092018100000.log:2:4", codes.OK},
+		{"regex", &pb.SearchRequest{Query: "^[15]$", Regex: true}, "app_01092018100000.log:1:1,app.log:1:5", codes.OK},
+		{"limit", &pb.SearchRequest{Query: "other", Limit: 1}, "app_notatime.log:1:other", codes.OK},
+		{"prefix", &pb.SearchRequest{Query: "1", PathPrefix: "none"}, "", codes.OK},
+		{"empty query", &pb.SearchRequest{}, "", codes.InvalidArgument},
+		{"bad regex", &pb.SearchRequest{Query: "(", Regex: true}, "", codes.InvalidArgument},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &searchStream{}
+			err := r.Search(tt.req, s)
+			if status.Code(err) != tt.code {
+				t.Fatalf("expected code %v and got %v", tt.code, status.Code(err))
+			}
+			got := make([]string, 0)
+			for _, res := range s.results {
+				if res.GetScribe() != "s1" {
+					t.Errorf("expected scribe s1 and got '%s'", res.GetScribe())
+				}
+				got = append(got, fmt.Sprintf("%s:%d:%s", res.GetSegment(), res.GetLineNumber(), res.GetLine()))
+			}
+			if strings.Join(got, ",") != tt.exp {
+				t.Errorf("expected '%s' and got '%s'", tt.exp, strings.Join(got, ","))
+			}
+		})
+	}
+}
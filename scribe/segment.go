@@ -0,0 +1,203 @@
+package scribe
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxLineSize is the biggest line the scribe reads back from its files
+	maxLineSize = 1024 * 1024
+	// rotationLayout is the UTC timestamp of the names of rotated segments,
+	// which sorts like the time it represents
+	rotationLayout = "20060102T150405Z"
+)
+
+// segment is a file holding part of the lines of a logical file.
+// A logical file consists of its rotated segments, named
+// filename_<timestamp>-<sequence>.log, or filename_<timestamp>-<sequence>.log.gz
+// when compressed, and its current segment filename.log. The sequence tells
+// apart the segments rotated during the same second. Segments named
+// filename_<timestamp>.log, after the legacy layout, are still read.
+type segment struct {
+	// file is the path of the segment
+	file string
+	// seq orders the segments rotated during the same second
+	seq int
+	// start and end define the time range the segment was written
+	start time.Time
+	end   time.Time
+}
+
+// overlaps checks if the segment was written during the time range.
+// A zero from or to means that the range is unbounded on that side.
+func (s segment) overlaps(from, to time.Time) bool {
+	if !to.IsZero() && s.start.After(to) {
+		return false
+	}
+	if !from.IsZero() && s.end.Before(from) {
+		return false
+	}
+	return true
+}
+
+// jsonTimePrefix starts every line of the json format
+const jsonTimePrefix = `{"time":"`
+
+// lineTime returns the time of a line written by the json format, or by
+// a text layout starting with {time}. Lines without one, i.e. of the raw
+// format, have none.
+func lineTime(line string) (time.Time, bool) {
+	var ts string
+	if strings.HasPrefix(line, jsonTimePrefix) {
+		ts = line[len(jsonTimePrefix):]
+		if i := strings.IndexByte(ts, '"'); i >= 0 {
+			ts = ts[:i]
+		}
+	} else if i := strings.IndexByte(line, ' '); i > 0 {
+		ts = line[:i]
+	}
+	if ts == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(timeLayout, ts)
+	return t, err == nil
+}
+
+// inRange checks if the line was written during the time range,
+// which the lines without a time of their own always are.
+func inRange(line string, from, to time.Time) bool {
+	t, ok := lineTime(line)
+	if !ok {
+		return true
+	}
+	return (from.IsZero() || !t.Before(from)) && (to.IsZero() || !t.After(to))
+}
+
+// segments lists the segments of a logical file, oldest first.
+// A logical file that doesn't exist has no segments.
+func segments(rootPath, path, filename string) ([]segment, error) {
+	dir := filepath.Join(rootPath, path)
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read directory '%s': %v", dir, err)
+	}
+
+	names := make(map[string]bool, len(infos))
+	for _, info := range infos {
+		names[info.Name()] = true
+	}
+
+	segs := make([]segment, 0)
+	var current *segment
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+		// a segment being compressed is read from the uncompressed copy
+		if strings.HasSuffix(info.Name(), gzipExt) && names[strings.TrimSuffix(info.Name(), gzipExt)] {
+			continue
+		}
+		if info.Name() == filename+".log" {
+			current = &segment{file: filepath.Join(dir, info.Name()), end: info.ModTime()}
+			continue
+		}
+		t, seq, ok := rotationTime(info.Name(), filename)
+		if !ok {
+			continue
+		}
+		segs = append(segs, segment{file: filepath.Join(dir, info.Name()), seq: seq, end: t})
+	}
+
+	sort.SliceStable(segs, func(i, j int) bool {
+		if !segs[i].end.Equal(segs[j].end) {
+			return segs[i].end.Before(segs[j].end)
+		}
+		return segs[i].seq < segs[j].seq
+	})
+	if current != nil {
+		segs = append(segs, *current)
+	}
+	for i := 1; i < len(segs); i++ {
+		segs[i].start = segs[i-1].end
+	}
+	return segs, nil
+}
+
+// segmentName returns the name of the segment of filename rotated at,
+// filename_<timestamp>-<sequence>.log
+func segmentName(filename string, at time.Time, seq int) string {
+	return fmt.Sprintf("%s_%s-%06d.log", filename, at.UTC().Format(rotationLayout), seq)
+}
+
+// rotationTime parses the time and the sequence of a rotated segment
+// of filename from its name, filename_<timestamp>-<sequence>.log,
+// or filename_<timestamp>.log for the legacy names, optionally
+// followed by the extension of the compressed segments.
+func rotationTime(name, filename string) (time.Time, int, bool) {
+	name = strings.TrimSuffix(name, gzipExt)
+	prefix := filename + "_"
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".log") {
+		return time.Time{}, 0, false
+	}
+	ts := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".log")
+	if t, err := time.ParseInLocation(layout, ts, time.Local); err == nil {
+		return t, 0, true
+	}
+	i := strings.LastIndex(ts, "-")
+	if i < 0 {
+		return time.Time{}, 0, false
+	}
+	t, err := time.Parse(rotationLayout, ts[:i])
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	seq, err := strconv.Atoi(ts[i+1:])
+	if err != nil || seq < 0 {
+		return time.Time{}, 0, false
+	}
+	return t, seq, true
+}
+
+// readLines calls fn for every line of the segment
+// until fn returns false or the segment ends.
+func (s segment) readLines(fn func(line string) bool) error {
+	f, err := os.Open(s.file)
+	if err != nil {
+		return fmt.Errorf("couldn't open segment '%s': %v", s.file, err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(s.file, gzipExt) {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("couldn't decompress segment '%s': %v", s.file, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		if !fn(scanner.Text()) {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("couldn't read segment '%s': %v", s.file, err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package scribe
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+)
+
+// createSegments creates a logical file "app" with two rotated segments,
+// a current one and a file of a different logical file with a similar name.
+func createSegments(t *testing.T) string {
+	root, err := ioutil.TempDir("", "segments")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	files := map[string]string{
+		"app_02092018100000.log":     "3\n4\n",
+		"app_01092018100000.log":     "1\n2\n",
+		"app.log":                    "5\n",
+		"app_x_01092018100000.log":   "other\n",
+		"app_notatime.log":           "other\n",
+		"application_01092018100000": "other\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+	return root
+}
+
+func TestSegments(t *testing.T) {
+	root := createSegments(t)
+	defer os.RemoveAll(root)
+
+	segs, err := segments(root, "", "app")
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	exp := []string{"app_01092018100000.log", "app_02092018100000.log", "app.log"}
+	if len(segs) != len(exp) {
+		t.Fatalf("expected %d segments and got %d", len(exp), len(segs))
+	}
+	for i, s := range segs {
+		if filepath.Base(s.file) != exp[i] {
+			t.Errorf("expected segment %d to be %s and got %s", i, exp[i], filepath.Base(s.file))
+		}
+		if i > 0 && !s.start.Equal(segs[i-1].end) {
+			t.Errorf("expected segment %d to start when the previous ended", i)
+		}
+	}
+
+	segs, err = segments(root, "none", "app")
+	if err != nil || len(segs) != 0 {
+		t.Errorf("expected no segments and no error, got %d and '%v'", len(segs), err)
+	}
+}
+
+func TestSegmentOverlaps(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2018, 9, d, 0, 0, 0, 0, time.UTC) }
+	s := segment{start: day(2), end: day(4)}
+
+	var tc = []struct {
+		from, to time.Time
+		exp      bool
+	}{
+		{time.Time{}, time.Time{}, true},
+		{day(1), day(3), true},
+		{day(3), time.Time{}, true},
+		{time.Time{}, day(1), false},
+		{day(5), time.Time{}, false},
+	}
+	for _, tt := range tc {
+		if got := s.overlaps(tt.from, tt.to); got != tt.exp {
+			t.Errorf("for %v - %v expected %v and got %v", tt.from, tt.to, tt.exp, got)
+		}
+	}
+}
+
+func TestLineTime(t *testing.T) {
+	at := time.Date(2018, 9, 1, 10, 0, 0, 5, time.UTC)
+	var tc = []struct {
+		line string
+		ok   bool
+	}{
+		{jsonFormatter{}.Format(&pb.LogRequest{Line: "x", Timestamp: at.UnixNano()}), true},
+		{textFormatter{layout: DefaultLayout}.Format(&pb.LogRequest{Line: "x", Timestamp: at.UnixNano()}), true},
+		{"x", false},
+		{"started at 2018-09-01T10:00:00Z", false},
+		{`{"time":"yesterday"}`, false},
+		{"", false},
+	}
+	for _, tt := range tc {
+		got, ok := lineTime(tt.line)
+		if ok != tt.ok || ok && !got.Equal(at) {
+			t.Errorf("expected %v, %v for %q and got %v, %v", at, tt.ok, tt.line, got, ok)
+		}
+	}
+}
+
+func TestRotationTime(t *testing.T) {
+	at := time.Date(2018, 9, 1, 10, 0, 0, 0, time.UTC)
+	var tc = []struct {
+		name string
+		t    time.Time
+		seq  int
+		ok   bool
+	}{
+		{segmentName("app", at, 0), at, 0, true},
+		{segmentName("app", at.In(time.Local), 12), at, 12, true},
+		{segmentName("app", at, 3) + gzipExt, at, 3, true},
+		{"app_" + at.Format(layout) + ".log", time.Date(2018, 9, 1, 10, 0, 0, 0, time.Local), 0, true},
+		{"app_20180901T100000Z.log", time.Time{}, 0, false},
+		{"app_20180901T100000Z-x.log", time.Time{}, 0, false},
+		{"app_20180901T100000Z--1.log", time.Time{}, 0, false},
+		{segmentName("other", at, 0), time.Time{}, 0, false},
+	}
+	for _, tt := range tc {
+		got, seq, ok := rotationTime(tt.name, "app")
+		if ok != tt.ok || !got.Equal(tt.t) || seq != tt.seq {
+			t.Errorf("for %s expected %v, %d, %v and got %v, %d, %v", tt.name, tt.t, tt.seq, tt.ok, got, seq, ok)
+		}
+	}
+}
+
+func TestRotateSameSecond(t *testing.T) {
+	root, err := ioutil.TempDir("", "segments")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	// a legacy segment rotated before the rest
+	legacy := "app_" + time.Now().Add(-time.Hour).Format(layout) + ".log"
+	ioutil.WriteFile(filepath.Join(root, legacy), []byte("0\n"), 0644)
+	c := newFileCache(1, 0)
+	defer c.closeAll()
+	// every write rotates the file, many times during the same second
+	for i := 1; i <= 20; i++ {
+		if err := c.write(root, "", "app", []string{fmt.Sprint(i)}, 1, pb.Ack_ACK_RECEIVED, nil); err != nil {
+			t.Fatalf("expected nil error and got '%v'", err)
+		}
+	}
+
+	segs, err := segments(root, "", "app")
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	if len(segs) != 21 {
+		t.Fatalf("expected 21 segments and got %d", len(segs))
+	}
+	names := make([]string, 0, len(segs))
+	for i, s := range segs {
+		b, _ := ioutil.ReadFile(s.file)
+		if string(b) != fmt.Sprintf("%d\n", i) {
+			t.Errorf("expected line %d in segment %s and got %q", i, s.file, string(b))
+		}
+		names = append(names, filepath.Base(s.file))
+	}
+	// the new names sort like their segments
+	rotated := names[1 : len(names)-1]
+	if !sort.StringsAreSorted(rotated) {
+		t.Errorf("expected sorted segment names and got %v", rotated)
+	}
+}
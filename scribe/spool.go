@@ -0,0 +1,231 @@
+package scribe
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	// maxSpoolSegment is the size after which the spool continues in a new segment
+	maxSpoolSegment = 64 * 1024 * 1024
+	// maxSpoolRecord is the biggest batch the spool reads back
+	maxSpoolRecord = 256 * 1024 * 1024
+	spoolPrefix    = "spool-"
+	spoolExt       = ".wal"
+)
+
+// spool is a write-ahead log of the accepted batches. A batch is appended
+// to the spool before it is acknowledged and marked done once written to
+// its files. The spool consists of segments, spool-<number>.wal, which get
+// deleted when every batch they hold is done. The segments found when
+// the spool opens hold the batches not written before the scribe stopped,
+// which get replayed, so a batch may be written twice but is never lost.
+//
+// Every record is the length and the CRC-32 of the batch, as 4 byte big
+// endian numbers, followed by the batch encoded as protobuf.
+type spool struct {
+	dir string
+
+	mu   sync.Mutex
+	f    *os.File
+	seg  int64
+	size int64
+	// pending has the number of batches not done of every segment
+	pending map[int64]int
+	closed  bool
+}
+
+// openSpool opens the spool in dir, creating the directory if missing,
+// and returns the segments left to replay, oldest first.
+func openSpool(dir string) (*spool, []string, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, nil, fmt.Errorf("couldn't create spool '%s': %v", dir, err)
+	}
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't read spool '%s': %v", dir, err)
+	}
+
+	s := &spool{dir: dir, pending: make(map[int64]int)}
+	segs := make([]int64, 0)
+	for _, info := range infos {
+		n, ok := spoolSegment(info.Name())
+		if !ok || info.IsDir() {
+			continue
+		}
+		segs = append(segs, n)
+		if n > s.seg {
+			s.seg = n
+		}
+	}
+	sort.Slice(segs, func(i, j int) bool { return segs[i] < segs[j] })
+	files := make([]string, 0, len(segs))
+	for _, n := range segs {
+		files = append(files, s.file(n))
+	}
+
+	if err := s.roll(); err != nil {
+		return nil, nil, err
+	}
+	return s, files, nil
+}
+
+// spoolSegment parses the number of a segment from its name
+func spoolSegment(name string) (int64, bool) {
+	if !strings.HasPrefix(name, spoolPrefix) || !strings.HasSuffix(name, spoolExt) {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(name, spoolPrefix), spoolExt), 10, 64)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+func (s *spool) file(seg int64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s%010d%s", spoolPrefix, seg, spoolExt))
+}
+
+// roll continues the spool in a new segment, deleting the
+// current one if done. Callers must hold mu, if needed.
+func (s *spool) roll() error {
+	if s.f != nil {
+		s.f.Close()
+		if s.pending[s.seg] == 0 {
+			delete(s.pending, s.seg)
+			os.Remove(s.file(s.seg))
+		}
+	}
+	s.seg++
+	f, err := os.OpenFile(s.file(s.seg), os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("couldn't create spool segment: %w", err)
+	}
+	s.f, s.size = f, 0
+	return nil
+}
+
+// append records the batch, syncing it to disk when sync is true,
+// and returns the segment it was recorded to.
+func (s *spool) append(b *pb.LogBatch, sync bool) (int64, error) {
+	data, err := proto.Marshal(b)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't encode batch: %v", err)
+	}
+	rec := make([]byte, 8+len(data))
+	binary.BigEndian.PutUint32(rec, uint32(len(data)))
+	binary.BigEndian.PutUint32(rec[4:], crc32.ChecksumIEEE(data))
+	copy(rec[8:], data)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return 0, fmt.Errorf("spool is closed")
+	}
+	if s.size > 0 && s.size+int64(len(rec)) > maxSpoolSegment {
+		if err := s.roll(); err != nil {
+			return 0, err
+		}
+	}
+	// a single write keeps a record whole unless the host crashes
+	if _, err := s.f.Write(rec); err != nil {
+		return 0, fmt.Errorf("couldn't write to spool: %w", err)
+	}
+	s.size += int64(len(rec))
+	if sync {
+		if err := s.f.Sync(); err != nil {
+			return 0, fmt.Errorf("couldn't sync spool: %w", err)
+		}
+	}
+	s.pending[s.seg]++
+	return s.seg, nil
+}
+
+// done marks a batch of the segment as written, deleting the segment
+// when it is done and full, or emptying it when done and current.
+func (s *spool) done(seg int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[seg]--
+	if s.pending[seg] > 0 {
+		return
+	}
+	if seg == s.seg {
+		// nothing is left to replay, unless the truncation fails
+		if !s.closed && s.f.Truncate(0) == nil {
+			s.size = 0
+		}
+		return
+	}
+	delete(s.pending, seg)
+	os.Remove(s.file(seg))
+}
+
+// close closes the current segment, deleting it when done. The segments
+// holding batches not done are kept to be replayed when the spool opens.
+func (s *spool) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	err := s.f.Close()
+	if s.pending[s.seg] == 0 {
+		os.Remove(s.file(s.seg))
+	}
+	return err
+}
+
+// replay calls fn for every batch recorded in the file, oldest first.
+// A record cut short or corrupted, i.e. by a crash while writing it,
+// ends the file, as nothing after it was acknowledged.
+func replay(file string, fn func(b *pb.LogBatch) error) (int, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't open spool segment '%s': %v", file, err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	header := make([]byte, 8)
+	n := 0
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			// io.EOF at the end of the file, io.ErrUnexpectedEOF at a cut header
+			return n, nil
+		}
+		size := binary.BigEndian.Uint32(header)
+		if size > maxSpoolRecord {
+			return n, nil
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return n, nil
+		}
+		if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[4:]) {
+			return n, nil
+		}
+		b := &pb.LogBatch{}
+		if err := proto.Unmarshal(data, b); err != nil {
+			return n, nil
+		}
+		if err := fn(b); err != nil {
+			return n, err
+		}
+		n++
+	}
+}
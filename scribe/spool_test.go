@@ -0,0 +1,230 @@
+package scribe
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func batch(filename string, lines ...string) *pb.LogBatch {
+	b := &pb.LogBatch{}
+	for _, l := range lines {
+		b.Entries = append(b.Entries, &pb.LogRequest{Filename: filename, Line: l})
+	}
+	return b
+}
+
+func TestSpool(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spool")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	sp, files, err := openSpool(dir)
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	if len(files) != 0 {
+		t.Fatalf("expected no segments to replay and got %v", files)
+	}
+	first, _ := sp.append(batch("a", "1"), false)
+	sp.append(batch("a", "2", "3"), true)
+	sp.done(first)
+	if err := sp.close(); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+
+	// a record cut short by a crash ends the segment
+	f, _ := os.OpenFile(sp.file(first), os.O_WRONLY|os.O_APPEND, 0644)
+	f.Write([]byte{0, 0, 0, 9, 1, 2})
+	f.Close()
+
+	sp, files, err = openSpool(dir)
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	defer sp.close()
+	if len(files) != 1 || files[0] != sp.file(first) {
+		t.Fatalf("expected the segment %s to replay and got %v", sp.file(first), files)
+	}
+	lines := make([]string, 0)
+	n, err := replay(files[0], func(b *pb.LogBatch) error {
+		for _, e := range b.GetEntries() {
+			lines = append(lines, e.GetLine())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	// the done batch is replayed too, as its segment wasn't deleted
+	if n != 2 || len(lines) != 3 || lines[2] != "3" {
+		t.Errorf("expected 2 batches with lines 1,2,3 and got %d with %v", n, lines)
+	}
+}
+
+func TestSpoolDone(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spool")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	sp, _, _ := openSpool(dir)
+	seg, _ := sp.append(batch("a", "1"), false)
+	if err := sp.roll(); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	// the previous segment is deleted when its batch is done
+	if _, err := os.Stat(sp.file(seg)); err != nil {
+		t.Fatalf("expected %s to be kept while its batch is pending", sp.file(seg))
+	}
+	sp.done(seg)
+	if _, err := os.Stat(sp.file(seg)); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be deleted", sp.file(seg))
+	}
+
+	// the current segment is deleted on close when done
+	cur, _ := sp.append(batch("a", "2"), false)
+	sp.done(cur)
+	sp.close()
+	if infos, _ := ioutil.ReadDir(dir); len(infos) != 0 {
+		t.Errorf("expected an empty spool and got %d segments", len(infos))
+	}
+	if _, err := sp.append(batch("a", "3"), false); err == nil {
+		t.Errorf("expected error appending to a closed spool")
+	}
+}
+
+func TestReplaySpool(t *testing.T) {
+	root, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+	dir := filepath.Join(root, "spool")
+
+	sp, _, _ := openSpool(dir)
+	sp.append(batch("a", "1", "2"), false)
+	sp.append(batch("../b", "3"), false)
+	sp.append(batch("a", "4"), false)
+	sp.close()
+
+	s := &LogScribe{target: target{rootPath: root, fileSize: -1, formatter: rawFormatter{},
+		tails: newTailHub(), files: newFileCache(0, 0)}}
+	if err := WithSpool(dir)(s); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	defer s.spool.close()
+	s.replaySpool()
+
+	b, err := ioutil.ReadFile(filepath.Join(root, "a.log"))
+	if err != nil {
+		t.Fatalf("failed to read a.log: %v", err)
+	}
+	// the invalid batch is skipped
+	if string(b) != "1\n2\n4\n" {
+		t.Errorf("expected lines 1,2,4 and got %q", b)
+	}
+	infos, _ := ioutil.ReadDir(dir)
+	if len(infos) != 1 || infos[0].Name() != filepath.Base(s.spool.file(s.spool.seg)) {
+		t.Errorf("expected only the current segment to be left and got %d segments", len(infos))
+	}
+}
+
+func TestSpoolCancelledPush(t *testing.T) {
+	root, err := ioutil.TempDir("", "cancel")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+	dir := filepath.Join(root, "spool")
+
+	s, err := New("test", filepath.Join(root, "logs"), 0, -1, "", "", "", "", WithSpool(dir))
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	defer s.spool.close()
+	l := s.Logger()
+
+	// the stream is blocked, as nothing handles it yet
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.Log(ctx, &pb.LogRequest{Filename: "a", Line: "lost"}); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected code %v and got '%v'", codes.DeadlineExceeded, err)
+	}
+	if len(s.spool.pending) != 0 {
+		t.Errorf("expected nothing pending in the spool and got %v", s.spool.pending)
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go s.serviceHandler(stop)
+	if _, err := l.Log(context.Background(), &pb.LogRequest{Filename: "a", Line: "1", Ack: pb.Ack_ACK_WRITTEN}); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	s.spool.mu.Lock()
+	pending, size := s.spool.pending[s.spool.seg], s.spool.size
+	s.spool.mu.Unlock()
+	if pending != 0 || size != 0 {
+		t.Errorf("expected the spool to be emptied and got %d pending in %d bytes", pending, size)
+	}
+	// only the batch taken gets written
+	b, _ := ioutil.ReadFile(filepath.Join(root, "logs", "a.log"))
+	if string(b) != "1\n" {
+		t.Errorf("expected line 1 and got %q", b)
+	}
+}
+
+func TestSpoolBuffered(t *testing.T) {
+	root, err := ioutil.TempDir("", "buffered")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	s, err := New("test", filepath.Join(root, "logs"), 0, -1, "", "", "", "",
+		WithSpool(filepath.Join(root, "spool")), WithFlush(FlushSize, 0, 0), WithWorkers(1, 0))
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	defer s.spool.close()
+	defer s.files.closeAll()
+	stop := make(chan struct{})
+	defer close(stop)
+	go s.serviceHandler(stop)
+	l := s.Logger()
+	pending := func() int {
+		s.spool.mu.Lock()
+		defer s.spool.mu.Unlock()
+		return s.spool.pending[s.spool.seg]
+	}
+
+	if _, err := l.LogBatch(context.Background(), &pb.LogBatch{Entries: []*pb.LogRequest{
+		{Filename: "a", Line: "1"},
+		{Filename: "b", Line: "1"},
+	}}); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	// the single worker writes the batches in order, flushing only a
+	if _, err := l.Log(context.Background(), &pb.LogRequest{Filename: "a", Line: "2", Ack: pb.Ack_ACK_WRITTEN}); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	if n := pending(); n != 1 {
+		t.Errorf("expected the batch buffered in b to stay in the spool and got %d pending", n)
+	}
+	if err := s.files.flushAll(); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	if n := pending(); n != 0 {
+		t.Errorf("expected the flushed batch to leave the spool and got %d pending", n)
+	}
+}
@@ -0,0 +1,101 @@
+package scribe
+
+import (
+	"sync"
+)
+
+// tailBuffer is the number of lines buffered for every follower
+// before it gets dropped for being too slow.
+const tailBuffer = 1024
+
+// tailStripes is the number of locks serializing the writes of the files
+const tailStripes = 64
+
+// tailHub delivers the written lines to the clients following a file.
+// Files are identified by the key filepath.Join(path, filename).
+type tailHub struct {
+	// stripes serialize the writes and the subscriptions of the files
+	// sharing a stripe, so that other files get written in parallel
+	stripes [tailStripes]sync.Mutex
+
+	mu   sync.Mutex
+	subs map[string]map[chan string]bool
+}
+
+func newTailHub() *tailHub {
+	return &tailHub{
+		subs: make(map[string]map[chan string]bool),
+	}
+}
+
+// stripe returns the lock of the file of key
+func (h *tailHub) stripe(key string) *sync.Mutex {
+	return &h.stripes[shard(key, tailStripes)]
+}
+
+// write calls fn, which writes the lines to the file of key, and delivers
+// the lines to the followers of the file. Holding the stripe of the file
+// while writing makes sure that a new follower gets every line exactly once.
+func (h *tailHub) write(key string, lines []string, fn func() error) error {
+	st := h.stripe(key)
+	st.Lock()
+	defer st.Unlock()
+	err := fn()
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs[key] {
+		if !deliver(ch, lines) {
+			// the follower is too slow, closing the channel lets it know
+			delete(h.subs[key], ch)
+			close(ch)
+		}
+	}
+	return nil
+}
+
+// subscribe adds a follower to the file of key. backlog is called under
+// the stripe of the file, so that no line gets written while reading it.
+func (h *tailHub) subscribe(key string, backlog func() ([]string, error)) (chan string, []string, error) {
+	st := h.stripe(key)
+	st.Lock()
+	defer st.Unlock()
+	lines, err := backlog()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan string, tailBuffer)
+	if _, ok := h.subs[key]; !ok {
+		h.subs[key] = make(map[chan string]bool)
+	}
+	h.subs[key][ch] = true
+	return ch, lines, nil
+}
+
+// unsubscribe removes a follower from the file of key
+func (h *tailHub) unsubscribe(key string, ch chan string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subs[key], ch)
+	if len(h.subs[key]) == 0 {
+		delete(h.subs, key)
+	}
+}
+
+func deliver(ch chan string, lines []string) bool {
+	for _, l := range lines {
+		select {
+		case ch <- l:
+		default:
+			return false
+		}
+	}
+	return true
+}
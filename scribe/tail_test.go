@@ -0,0 +1,55 @@
+package scribe
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTailHub(t *testing.T) {
+	h := newTailHub()
+	ch, backlog, err := h.subscribe("p/f", func() ([]string, error) {
+		return []string{"old"}, nil
+	})
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	if len(backlog) != 1 || backlog[0] != "old" {
+		t.Errorf("expected backlog [old] and got %v", backlog)
+	}
+
+	err = h.write("p/f", []string{"1", "2"}, func() error { return nil })
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	// lines of other files and failed writes are not delivered
+	h.write("p/g", []string{"x"}, func() error { return nil })
+	h.write("p/f", []string{"y"}, func() error { return errors.New("failed") })
+
+	for _, exp := range []string{"1", "2"} {
+		if got := <-ch; got != exp {
+			t.Errorf("expected %q and got %q", exp, got)
+		}
+	}
+	if len(ch) != 0 {
+		t.Errorf("expected no more lines and got %d", len(ch))
+	}
+
+	h.unsubscribe("p/f", ch)
+	if len(h.subs) != 0 {
+		t.Errorf("expected no followers and got %d", len(h.subs))
+	}
+}
+
+func TestTailHubSlowFollower(t *testing.T) {
+	h := newTailHub()
+	ch, _, _ := h.subscribe("f", func() ([]string, error) { return nil, nil })
+
+	lines := make([]string, tailBuffer+1)
+	h.write("f", lines, func() error { return nil })
+	for range ch {
+		// drain until the hub closes the channel
+	}
+	if len(h.subs["f"]) != 0 {
+		t.Errorf("expected slow follower to be dropped")
+	}
+}
@@ -4,6 +4,12 @@ type target struct {
 	isFile   bool
 	rootPath string
 	fileSize int64
+	// formatter renders the requests to the persisted lines
+	formatter Formatter
+	// tails delivers the written lines to the clients following them
+	tails *tailHub
+	// files keeps the files written lately open
+	files *fileCache
 }
 
 func createTarget(root string, fileSize int64) (*target, error) {
@@ -13,9 +19,12 @@ func createTarget(root string, fileSize int64) (*target, error) {
 	}
 
 	target := &target{
-		isFile:   true,
-		rootPath: root,
-		fileSize: fileSize,
+		isFile:    true,
+		rootPath:  root,
+		fileSize:  fileSize,
+		formatter: rawFormatter{},
+		tails:     newTailHub(),
+		files:     newFileCache(defaultMaxOpenFiles, defaultIdleTimeout),
 	}
 
 	return target, nil
@@ -3,6 +3,9 @@ package scribe
 import (
 	"fmt"
 	"strconv"
+	"strings"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
 )
 
 const (
@@ -41,3 +44,49 @@ func LexicalToNumber(size string) (int64, error) {
 	}
 	return int64(s * m), nil
 }
+
+// ParseAck converts an acknowledgement mode name to pb.Ack.
+// An empty mode is the same as received.
+func ParseAck(mode string) (pb.Ack, error) {
+	switch mode {
+	case "", "received":
+		return pb.Ack_ACK_RECEIVED, nil
+	case "written":
+		return pb.Ack_ACK_WRITTEN, nil
+	case "synced":
+		return pb.Ack_ACK_SYNCED, nil
+	default:
+		return pb.Ack_ACK_DEFAULT, fmt.Errorf("unknown acknowledgement mode '%s'", mode)
+	}
+}
+
+// ParseKeep converts the name of the lowest severity written while the disk
+// is low to pb.Severity: all, debug, info, warning, error, fatal or none.
+// An empty name is the same as warning.
+func ParseKeep(name string) (pb.Severity, error) {
+	switch name {
+	case "":
+		return pb.Severity_WARNING, nil
+	case "all":
+		return pb.Severity_UNSPECIFIED, nil
+	case "none":
+		return KeepNone, nil
+	}
+	v, ok := pb.Severity_value[strings.ToUpper(name)]
+	if !ok || v == int32(pb.Severity_UNSPECIFIED) {
+		return 0, fmt.Errorf("unknown severity '%s'", name)
+	}
+	return pb.Severity(v), nil
+}
+
+// keepName is the name of the severity kept while the disk is low,
+// as given to ParseKeep.
+func keepName(keep pb.Severity) string {
+	switch keep {
+	case pb.Severity_UNSPECIFIED:
+		return "all"
+	case KeepNone:
+		return "none"
+	}
+	return strings.ToLower(keep.String())
+}
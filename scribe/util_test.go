@@ -1,6 +1,10 @@
 package scribe
 
-import "testing"
+import (
+	"testing"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+)
 
 func TestLexicalToNumber(t *testing.T) {
 	type testCase struct {
@@ -30,3 +34,57 @@ func TestLexicalToNumber(t *testing.T) {
 		}
 	}
 }
+
+func TestParseAck(t *testing.T) {
+	var tc = []struct {
+		mode string
+		ack  pb.Ack
+		err  bool
+	}{
+		{"", pb.Ack_ACK_RECEIVED, false},
+		{"received", pb.Ack_ACK_RECEIVED, false},
+		{"written", pb.Ack_ACK_WRITTEN, false},
+		{"synced", pb.Ack_ACK_SYNCED, false},
+		{"never", pb.Ack_ACK_DEFAULT, true},
+	}
+	for _, tt := range tc {
+		ack, err := ParseAck(tt.mode)
+		if tt.err && err == nil {
+			t.Errorf("For %s expected an error, but got %v", tt.mode, ack)
+		}
+		if !tt.err && err != nil {
+			t.Errorf("For %s expected %v, but got err '%v'", tt.mode, tt.ack, err)
+		}
+		if ack != tt.ack {
+			t.Errorf("For %s expected %v, but got %v", tt.mode, tt.ack, ack)
+		}
+	}
+}
+
+func TestParseKeep(t *testing.T) {
+	var tc = []struct {
+		name string
+		keep pb.Severity
+		err  bool
+	}{
+		{"", pb.Severity_WARNING, false},
+		{"all", pb.Severity_UNSPECIFIED, false},
+		{"debug", pb.Severity_DEBUG, false},
+		{"error", pb.Severity_ERROR, false},
+		{"none", KeepNone, false},
+		{"unspecified", 0, true},
+		{"loud", 0, true},
+	}
+	for _, tt := range tc {
+		keep, err := ParseKeep(tt.name)
+		if tt.err != (err != nil) {
+			t.Errorf("For %s expected error %v, but got '%v'", tt.name, tt.err, err)
+		}
+		if keep != tt.keep {
+			t.Errorf("For %s expected %v, but got %v", tt.name, tt.keep, keep)
+		}
+		if name := keepName(keep); !tt.err && tt.name != "" && name != tt.name {
+			t.Errorf("For %s expected the same name, but got %s", tt.name, name)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package scribe
+
+import (
+	"hash/fnv"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	"github.com/RomanosTrechlis/go-scribe/service"
+)
+
+// defaultQueueDepth is the number of parts of batches queued for every worker
+const defaultQueueDepth = 64
+
+// part is the share of a batch written by a single worker
+type part struct {
+	entries []*pb.LogRequest
+	ack     pb.Ack
+	batch   *pendingBatch
+}
+
+// pendingBatch tracks the parts of a batch until every one is written,
+// and the lines of a spooled batch until flushed to their files.
+type pendingBatch struct {
+	entry service.Entry
+	// spool has the batch recorded to its segment spooled, when not nil
+	spool   *spool
+	spooled int64
+
+	mu   sync.Mutex
+	left int
+	err  error
+	// buffered is the number of files holding lines of the batch not flushed
+	buffered int
+}
+
+// finish marks a part as written, or failed, and reports whether it was
+// the last one along with the first error of the parts of the batch.
+func (b *pendingBatch) finish(err error) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err == nil {
+		b.err = err
+	}
+	b.left--
+	if b.left == 0 && b.buffered == 0 {
+		b.unspool()
+	}
+	return b.left == 0, b.err
+}
+
+// hold marks lines of the batch as written to a file but not yet flushed
+func (b *pendingBatch) hold() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buffered++
+}
+
+// flushed marks the lines of the batch held for a file as flushed
+func (b *pendingBatch) flushed() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buffered--
+	if b.left == 0 && b.buffered == 0 {
+		b.unspool()
+	}
+}
+
+// unspool marks the batch done in the spool, once every part is finished
+// and every line flushed, so that buffered lines are replayed after a crash.
+func (b *pendingBatch) unspool() {
+	if b.spool != nil {
+		b.spool.done(b.spooled)
+	}
+}
+
+// shard returns the one, out of n, responsible for the file of key
+func shard(key string, n int) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int(h.Sum32() % uint32(n))
+}
+
+// split divides the entries among n workers by their file,
+// keeping the order of the entries of every file.
+func split(entries []*pb.LogRequest, n int) [][]*pb.LogRequest {
+	parts := make([][]*pb.LogRequest, n)
+	for _, e := range entries {
+		path, err := service.ValidateFile("", e.GetPath(), e.GetFilename())
+		if err != nil {
+			// the write rejects it anyway
+			path = e.GetPath()
+		}
+		i := shard(filepath.Join(path, e.GetFilename()), n)
+		parts[i] = append(parts[i], e)
+	}
+	return parts
+}
+
+// dispatch queues the parts of the batch, recorded to the spooled segment
+// unless zero, to the workers of their files, waiting while the queues are
+// full, until stop is closed.
+func (s *LogScribe) dispatch(queues []chan part, req service.Entry, spooled int64, stop chan struct{}) {
+	parts := split(req.Batch.GetEntries(), len(queues))
+	b := &pendingBatch{entry: req, spooled: spooled}
+	if spooled != 0 {
+		b.spool = s.spool
+	}
+	for _, entries := range parts {
+		if len(entries) > 0 {
+			b.left++
+		}
+	}
+	if b.left == 0 {
+		b.unspool()
+		s.complete(req, nil)
+		return
+	}
+
+	for i, entries := range parts {
+		if len(entries) == 0 {
+			continue
+		}
+		pt := part{entries: entries, ack: req.Ack, batch: b}
+		select {
+		case queues[i] <- pt:
+			// queued while there is room, even when stopping
+			continue
+		default:
+		}
+		select {
+		case queues[i] <- pt:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// worker writes the parts of its queue, one after the other, until the queue
+// is closed. The parts failing are handled by the error policy, so that a
+// failure never stops the worker, and once stop is closed the failed writes
+// are no longer retried.
+func (s *LogScribe) worker(queue chan part, stop chan struct{}) {
+	for pt := range queue {
+		err := s.writePart(pt, stop)
+		if err == errStopped {
+			// the batch is left unfinished, and in the spool if spooled
+			continue
+		}
+		if last, err := pt.batch.finish(err); last {
+			s.complete(pt.batch.entry, err)
+		}
+	}
+}
+
+// writePart writes the entries of the part file by file, retrying the failed
+// writes as the error policy defines, so that a failure of a file doesn't
+// write the others twice. The entries still failing are dropped or
+// dead-lettered, unless the client waits for the result, and the first
+// error is returned. The lines of a spooled batch are held until flushed.
+func (s *LogScribe) writePart(pt part, stop chan struct{}) error {
+	var failed error
+	for _, entries := range byFile(pt.entries) {
+		err := s.retry(func() error {
+			if pt.batch.spool == nil {
+				return s.writeBatch(entries, pt.ack, nil)
+			}
+			pt.batch.hold()
+			err := s.writeBatch(entries, pt.ack, pt.batch.flushed)
+			if err != nil {
+				// nothing of the file is left buffered
+				pt.batch.flushed()
+			}
+			return err
+		}, stop)
+		if err == nil {
+			continue
+		}
+		if err == errStopped {
+			return err
+		}
+		atomic.AddInt64(&s.errStats.Failed, int64(len(entries)))
+		if pt.batch.entry.Done == nil {
+			s.discard(entries, err)
+		}
+		if failed == nil {
+			failed = err
+		}
+	}
+	return failed
+}
+
+// byFile groups the entries by their file, keeping their order
+func byFile(entries []*pb.LogRequest) [][]*pb.LogRequest {
+	groups := make([][]*pb.LogRequest, 0)
+	index := make(map[string]int)
+	for _, e := range entries {
+		key := filepath.Join(e.GetPath(), e.GetFilename())
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], e)
+	}
+	return groups
+}
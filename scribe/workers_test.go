@@ -0,0 +1,135 @@
+package scribe
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	"golang.org/x/net/context"
+)
+
+func TestSplit(t *testing.T) {
+	entries := []*pb.LogRequest{
+		{Filename: "a", Line: "1"},
+		{Filename: "b", Path: "p", Line: "2"},
+		{Filename: "a", Path: "./", Line: "3"},
+		{Filename: "b", Path: "p/", Line: "4"},
+	}
+	parts := split(entries, 8)
+	if len(parts) != 8 {
+		t.Fatalf("expected 8 parts and got %d", len(parts))
+	}
+	lines := make(map[string]string)
+	for _, p := range parts {
+		for _, e := range p {
+			lines[e.GetFilename()] += e.GetLine()
+		}
+		if len(p) > 0 && len(p) != 2 && len(p) != 4 {
+			t.Errorf("expected the entries of a file in the same part and got %d entries", len(p))
+		}
+	}
+	if lines["a"] != "13" || lines["b"] != "24" {
+		t.Errorf("expected the order of every file to be kept and got %v", lines)
+	}
+}
+
+func TestWorkers(t *testing.T) {
+	root, err := ioutil.TempDir("", "workers")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	s, err := New("test", root, 0, -1, "", "", "", "", WithWorkers(4, 2), WithAck("written"))
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	go s.serviceHandler(stop)
+
+	// every client writes its lines in order to its file and to a shared one
+	l := s.Logger()
+	var wg sync.WaitGroup
+	for c := 0; c < 8; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				line := fmt.Sprintf("%d-%d", c, i)
+				_, err := l.LogBatch(context.Background(), &pb.LogBatch{Entries: []*pb.LogRequest{
+					{Filename: fmt.Sprintf("f%d", c), Line: line},
+					{Filename: "shared", Line: line},
+				}})
+				if err != nil {
+					t.Errorf("expected nil error and got '%v'", err)
+					return
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+	s.files.closeAll()
+
+	shared := make(map[int]int)
+	b, _ := ioutil.ReadFile(filepath.Join(root, "shared.log"))
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		var c, i int
+		fmt.Sscanf(line, "%d-%d", &c, &i)
+		if last, ok := shared[c]; ok && i != last+1 {
+			t.Fatalf("expected line %d-%d after %d-%d", c, last+1, c, last)
+		}
+		shared[c] = i
+	}
+	for c := 0; c < 8; c++ {
+		if shared[c] != 49 {
+			t.Errorf("expected the 50 lines of client %d in the shared file", c)
+		}
+		b, _ := ioutil.ReadFile(filepath.Join(root, fmt.Sprintf("f%d.log", c)))
+		if lines := strings.Split(strings.TrimSpace(string(b)), "\n"); len(lines) != 50 || lines[49] != fmt.Sprintf("%d-49", c) {
+			t.Errorf("expected the 50 lines of client %d in order and got %d", c, len(lines))
+		}
+	}
+}
+
+func TestWorkersDrain(t *testing.T) {
+	root, err := ioutil.TempDir("", "workers")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	s, err := New("test", root, 0, -1, "", "", "", "", WithWorkers(1, 64), WithAck("received"))
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		s.serviceHandler(stop)
+	}()
+
+	l := s.Logger()
+	for i := 0; i < 50; i++ {
+		_, err := l.LogBatch(context.Background(), &pb.LogBatch{Entries: []*pb.LogRequest{
+			{Filename: "drain", Line: fmt.Sprint(i)},
+		}})
+		if err != nil {
+			t.Fatalf("expected nil error and got '%v'", err)
+		}
+	}
+	close(stop)
+	<-stopped
+	s.files.closeAll()
+
+	b, _ := ioutil.ReadFile(filepath.Join(root, "drain.log"))
+	if lines := strings.Split(strings.TrimSpace(string(b)), "\n"); len(lines) != 50 {
+		t.Errorf("expected the 50 queued lines written before stopping and got %d", len(lines))
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/net/context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// InvalidArgument creates an InvalidArgument error
+// describing which field of the request is not valid.
+func InvalidArgument(field, description string) error {
+	st := status.New(codes.InvalidArgument, field+": "+description)
+	return withDetails(st, &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: field, Description: description},
+		},
+	})
+}
+
+// Unavailable creates an Unavailable error
+// telling the client when it is worth retrying.
+func Unavailable(msg string, retry time.Duration) error {
+	st := status.New(codes.Unavailable, msg)
+	return withDetails(st, &errdetails.RetryInfo{
+		RetryDelay: ptypes.DurationProto(retry),
+	})
+}
+
+// ResourceExhausted creates a ResourceExhausted error
+// for the resource, usually a path, that ran out.
+func ResourceExhausted(resource, msg string) error {
+	st := status.New(codes.ResourceExhausted, msg)
+	return withDetails(st, &errdetails.ResourceInfo{
+		ResourceType: "file",
+		ResourceName: resource,
+		Description:  msg,
+	})
+}
+
+// Status converts an error returned while handling a request to a
+// status error. Errors that already carry a status are returned as is,
+// while the rest get a code depending on their cause.
+func Status(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case err == context.Canceled:
+		return status.Error(codes.Canceled, err.Error())
+	case err == context.DeadlineExceeded:
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, syscall.ENOSPC), errors.Is(err, syscall.EDQUOT):
+		var pathErr *os.PathError
+		if errors.As(err, &pathErr) {
+			return ResourceExhausted(pathErr.Path, err.Error())
+		}
+		return ResourceExhausted("", err.Error())
+	case errors.Is(err, os.ErrPermission):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, syscall.ENOTDIR), errors.Is(err, syscall.EISDIR):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func withDetails(st *status.Status, details ...proto.Message) error {
+	ds, err := st.WithDetails(details...)
+	if err != nil {
+		// details are only informative, return the plain status
+		return st.Err()
+	}
+	return ds.Err()
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatus(t *testing.T) {
+	var tests = []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{"nil", nil, codes.OK},
+		{"status", status.Error(codes.NotFound, "not found"), codes.NotFound},
+		{"canceled", context.Canceled, codes.Canceled},
+		{"deadline", context.DeadlineExceeded, codes.DeadlineExceeded},
+		{"disk full", fmt.Errorf("write: %w", &os.PathError{Op: "write", Path: "a.log", Err: syscall.ENOSPC}),
+			codes.ResourceExhausted},
+		{"permission", fmt.Errorf("open: %w", &os.PathError{Op: "open", Path: "a.log", Err: os.ErrPermission}),
+			codes.PermissionDenied},
+		{"not a directory", fmt.Errorf("open: %w", &os.PathError{Op: "open", Path: "a/b.log", Err: syscall.ENOTDIR}),
+			codes.FailedPrecondition},
+		{"other", errors.New("something"), codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := status.Code(Status(tt.err))
+			if got != tt.code {
+				t.Errorf("expected code %v and got %v", tt.code, got)
+			}
+		})
+	}
+}
+
+func TestInvalidArgument(t *testing.T) {
+	err := InvalidArgument("filename", "must not be empty")
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected code %v and got %v", codes.InvalidArgument, st.Code())
+	}
+	if len(st.Details()) != 1 {
+		t.Fatalf("expected 1 detail and got %d", len(st.Details()))
+	}
+	br, ok := st.Details()[0].(*errdetails.BadRequest)
+	if !ok {
+		t.Fatalf("expected a BadRequest detail and got %T", st.Details()[0])
+	}
+	if br.GetFieldViolations()[0].GetField() != "filename" {
+		t.Errorf("expected violation of 'filename' and got '%s'", br.GetFieldViolations()[0].GetField())
+	}
+}
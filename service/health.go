@@ -0,0 +1,143 @@
+package service
+
+import (
+	"sync"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// The names of the services reported by the health service
+const (
+	LogScribeService = "com.romanostrechlis.scribe.api.LogScribe"
+	LogReaderService = "com.romanostrechlis.scribe.api.LogReader"
+	RegisterService  = "com.romanostrechlis.scribe.api.Register"
+)
+
+// Health implements the standard gRPC health service, grpc.health.v1.Health,
+// reporting the serving status of every service. The server as a whole,
+// the empty service name, is serving only when every service is serving.
+type Health struct {
+	mu       sync.Mutex
+	statuses map[string]healthpb.HealthCheckResponse_ServingStatus
+	// watchers are notified when the status of a service changes
+	watchers map[string]map[chan healthpb.HealthCheckResponse_ServingStatus]bool
+	shutdown bool
+}
+
+// NewHealth creates a health service with the services serving
+func NewHealth(services ...string) *Health {
+	h := &Health{
+		statuses: make(map[string]healthpb.HealthCheckResponse_ServingStatus),
+		watchers: make(map[string]map[chan healthpb.HealthCheckResponse_ServingStatus]bool),
+	}
+	for _, s := range services {
+		h.statuses[s] = healthpb.HealthCheckResponse_SERVING
+	}
+	h.statuses[""] = healthpb.HealthCheckResponse_SERVING
+	return h
+}
+
+// Check implements the Check protobuf service
+func (h *Health) Check(ctx context.Context, in *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	st, ok := h.statuses[in.GetService()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown service '%s'", in.GetService())
+	}
+	return &healthpb.HealthCheckResponse{Status: st}, nil
+}
+
+// Watch implements the Watch protobuf service by sending
+// the status of the service and then every change of it.
+func (h *Health) Watch(in *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	service := in.GetService()
+	ch := make(chan healthpb.HealthCheckResponse_ServingStatus, 1)
+	h.mu.Lock()
+	st, ok := h.statuses[service]
+	if !ok {
+		st = healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+	}
+	ch <- st
+	if _, ok := h.watchers[service]; !ok {
+		h.watchers[service] = make(map[chan healthpb.HealthCheckResponse_ServingStatus]bool)
+	}
+	h.watchers[service][ch] = true
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		delete(h.watchers[service], ch)
+		if len(h.watchers[service]) == 0 {
+			delete(h.watchers, service)
+		}
+		h.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case st := <-ch:
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: st}); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return status.Error(codes.Canceled, "stream has ended")
+		}
+	}
+}
+
+// SetServing sets whether the service is serving,
+// after a shutdown every service stays not serving.
+func (h *Health) SetServing(service string, serving bool) {
+	st := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		st = healthpb.HealthCheckResponse_SERVING
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.shutdown {
+		return
+	}
+	h.set(service, st)
+	h.set("", h.overall())
+}
+
+// Shutdown sets every service as not serving
+func (h *Health) Shutdown() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.shutdown = true
+	for service := range h.statuses {
+		h.set(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+}
+
+// set changes the status of the service and notifies
+// its watchers. Callers must hold the lock.
+func (h *Health) set(service string, st healthpb.HealthCheckResponse_ServingStatus) {
+	if old, ok := h.statuses[service]; ok && old == st {
+		return
+	}
+	h.statuses[service] = st
+	for ch := range h.watchers[service] {
+		// watchers only care about the latest status
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- st
+	}
+}
+
+// overall is serving when every service is serving. Callers must hold the lock.
+func (h *Health) overall() healthpb.HealthCheckResponse_ServingStatus {
+	for service, st := range h.statuses {
+		if service != "" && st != healthpb.HealthCheckResponse_SERVING {
+			return healthpb.HealthCheckResponse_NOT_SERVING
+		}
+	}
+	return healthpb.HealthCheckResponse_SERVING
+}
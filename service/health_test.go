@@ -0,0 +1,97 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestHealth(t *testing.T) {
+	h := NewHealth(LogScribeService, LogReaderService)
+	check := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		r, err := h.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("expected nil error and got '%v'", err)
+		}
+		return r.GetStatus()
+	}
+
+	if st := check(""); st != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected the server to be serving and got %v", st)
+	}
+	h.SetServing(LogScribeService, false)
+	if st := check(LogScribeService); st != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected LogScribe not to be serving and got %v", st)
+	}
+	if st := check(LogReaderService); st != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected LogReader to be serving and got %v", st)
+	}
+	if st := check(""); st != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected the server not to be serving and got %v", st)
+	}
+	h.SetServing(LogScribeService, true)
+	if st := check(""); st != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected the server to be serving again and got %v", st)
+	}
+
+	h.Shutdown()
+	h.SetServing(LogReaderService, true)
+	if st := check(LogReaderService); st != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("expected LogReader not to be serving after shutdown and got %v", st)
+	}
+
+	_, err := h.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected code %v and got %v", codes.NotFound, status.Code(err))
+	}
+}
+
+// watchStream collects the statuses sent by Watch
+type watchStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	statuses chan healthpb.HealthCheckResponse_ServingStatus
+}
+
+func (s *watchStream) Send(r *healthpb.HealthCheckResponse) error {
+	s.statuses <- r.GetStatus()
+	return nil
+}
+
+func (s *watchStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestHealthWatch(t *testing.T) {
+	h := NewHealth(LogScribeService)
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &watchStream{ctx: ctx, statuses: make(chan healthpb.HealthCheckResponse_ServingStatus, 4)}
+	done := make(chan error)
+	go func() {
+		done <- h.Watch(&healthpb.HealthCheckRequest{Service: LogScribeService}, s)
+	}()
+
+	expect := func(exp healthpb.HealthCheckResponse_ServingStatus) {
+		select {
+		case st := <-s.statuses:
+			if st != exp {
+				t.Errorf("expected %v and got %v", exp, st)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected status %v", exp)
+		}
+	}
+	expect(healthpb.HealthCheckResponse_SERVING)
+	h.SetServing(LogScribeService, false)
+	expect(healthpb.HealthCheckResponse_NOT_SERVING)
+
+	cancel()
+	if err := <-done; status.Code(err) != codes.Canceled {
+		t.Errorf("expected code %v and got %v", codes.Canceled, status.Code(err))
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	"github.com/golang/protobuf/jsonpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// LogPath is the path of the HTTP endpoint receiving requests
+	LogPath = "/v1/log"
+	// maxBodySize is the biggest body accepted by the HTTP endpoint
+	maxBodySize = 32 * 1024 * 1024
+)
+
+// ServeHTTP implements http.Handler for the clients that can't use gRPC.
+// The body is a single JSON request, or many of them delimited by newlines
+// (NDJSON), using the field names of LogRequest. The requests are pushed to
+// the stream as one batch, acknowledged as the ack query parameter asks,
+// i.e. ?ack=written, and the response is a JSON object with their count.
+// Failures are reported with the HTTP status matching the gRPC status code.
+func (l Logger) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeStatus(w, http.StatusMethodNotAllowed, status.New(codes.Unimplemented, "method must be POST"))
+		return
+	}
+
+	ack, err := parseAck(r.URL.Query().Get("ack"))
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+	b, err := decodeBatch(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+	for i, e := range b.GetEntries() {
+		if err := validate(fmt.Sprintf("entries[%d].", i), e); err != nil {
+			writeHTTPError(w, err)
+			return
+		}
+	}
+
+	if err := l.push(r.Context(), &b, ack); err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	m := jsonpb.Marshaler{OrigName: true, EmitDefaults: true}
+	m.Marshal(w, &pb.LogBatchResponse{Count: int64(len(b.GetEntries()))})
+}
+
+// decodeBatch decodes the JSON requests of the body
+func decodeBatch(body io.Reader) (pb.LogBatch, error) {
+	b := pb.LogBatch{Entries: make([]*pb.LogRequest, 0)}
+	dec := json.NewDecoder(body)
+	u := jsonpb.Unmarshaler{}
+	for {
+		in := new(pb.LogRequest)
+		err := u.UnmarshalNext(dec, in)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return b, InvalidArgument(fmt.Sprintf("entries[%d]", len(b.Entries)), err.Error())
+		}
+		b.Entries = append(b.Entries, in)
+	}
+	if len(b.Entries) == 0 {
+		return b, InvalidArgument("body", "must contain at least one request")
+	}
+	return b, nil
+}
+
+// parseAck parses the acknowledgement mode,
+// either by its name, i.e. ACK_WRITTEN, or without the prefix.
+func parseAck(mode string) (pb.Ack, error) {
+	if mode == "" {
+		return pb.Ack_ACK_DEFAULT, nil
+	}
+	name := strings.ToUpper(mode)
+	if !strings.HasPrefix(name, "ACK_") {
+		name = "ACK_" + name
+	}
+	ack, ok := pb.Ack_value[name]
+	if !ok {
+		return pb.Ack_ACK_DEFAULT, InvalidArgument("ack", fmt.Sprintf("unknown acknowledgement mode '%s'", mode))
+	}
+	return pb.Ack(ack), nil
+}
+
+// writeHTTPError writes the error as a JSON object
+// with the HTTP status matching its gRPC status code.
+func writeHTTPError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	writeStatus(w, httpStatus(st.Code()), st)
+}
+
+// writeStatus writes the status as a JSON object with the HTTP status code
+func writeStatus(w http.ResponseWriter, code int, st *status.Status) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}{st.Code().String(), st.Message()})
+}
+
+// httpStatus maps a gRPC status code to an HTTP status
+func httpStatus(c codes.Code) int {
+	switch c {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		return 499
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+)
+
+func TestServeHTTP(t *testing.T) {
+	stream := make(chan Entry, 1)
+	l := Logger{Stream: stream, Stop: make(chan struct{})}
+
+	var tc = []struct {
+		name   string
+		method string
+		target string
+		body   string
+		code   int
+		count  int
+	}{
+		{"single", "POST", "/v1/log", `{"filename":"app","line":"hello","severity":"ERROR"}`, http.StatusOK, 1},
+		{"ndjson", "POST", "/v1/log", "{\"filename\":\"app\",\"line\":\"1\"}\n{\"filename\":\"app\",\"line\":\"2\"}\n", http.StatusOK, 2},
+		{"written", "POST", "/v1/log?ack=written", `{"filename":"app","line":"hello"}`, http.StatusOK, 1},
+		{"failed write", "POST", "/v1/log?ack=written", `{"filename":"fail","line":"hello"}`, http.StatusInternalServerError, 0},
+		{"no filename", "POST", "/v1/log", `{"line":"hello"}`, http.StatusBadRequest, 0},
+		{"malformed", "POST", "/v1/log", `{"filename":`, http.StatusBadRequest, 0},
+		{"empty", "POST", "/v1/log", ``, http.StatusBadRequest, 0},
+		{"bad ack", "POST", "/v1/log?ack=never", `{"filename":"app"}`, http.StatusBadRequest, 0},
+		{"method", "GET", "/v1/log", ``, http.StatusMethodNotAllowed, 0},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			go func() {
+				select {
+				case e := <-stream:
+					if e.Done == nil {
+						return
+					}
+					if e.Batch.GetEntries()[0].GetFilename() == "fail" {
+						e.Done <- errors.New("failed")
+						return
+					}
+					e.Done <- nil
+				case <-l.Stop:
+				}
+			}()
+
+			rec := httptest.NewRecorder()
+			l.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			if rec.Code != tt.code {
+				t.Fatalf("expected status %d and got %d: %s", tt.code, rec.Code, rec.Body.String())
+			}
+			if tt.code == http.StatusOK && !strings.Contains(rec.Body.String(), fmt.Sprintf(`"count":"%d"`, tt.count)) {
+				t.Errorf("expected count %d and got %s", tt.count, rec.Body.String())
+			}
+		})
+	}
+	close(l.Stop)
+}
+
+func TestParseAck(t *testing.T) {
+	for mode, exp := range map[string]pb.Ack{
+		"":           pb.Ack_ACK_DEFAULT,
+		"written":    pb.Ack_ACK_WRITTEN,
+		"ACK_SYNCED": pb.Ack_ACK_SYNCED,
+		"Received":   pb.Ack_ACK_RECEIVED,
+	} {
+		got, err := parseAck(mode)
+		if err != nil || got != exp {
+			t.Errorf("for '%s' expected %v and got %v, '%v'", mode, exp, got, err)
+		}
+	}
+}
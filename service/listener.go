@@ -0,0 +1,97 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	p "github.com/RomanosTrechlis/go-scribe/internal/util/format/print"
+)
+
+// listeners keeps track of the listeners and the connections
+// of a server, so that closing the server closes them all.
+type listeners struct {
+	mu        sync.Mutex
+	closers   map[io.Closer]bool
+	wg        sync.WaitGroup
+	closed    bool
+	closeOnce sync.Once
+}
+
+func newListeners() listeners {
+	return listeners{closers: make(map[io.Closer]bool)}
+}
+
+// serve accepts the connections of the listener,
+// handling each one on its own goroutine.
+func (ls *listeners) serve(lis net.Listener, name string, handle func(net.Conn)) error {
+	if err := ls.track(lis); err != nil {
+		return err
+	}
+
+	ls.wg.Add(1)
+	go func() {
+		defer ls.wg.Done()
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				if !ls.isClosed() {
+					p.Print(fmt.Sprintf("%s listener stopped: %v", name, err))
+				}
+				return
+			}
+			if err := ls.track(conn); err != nil {
+				return
+			}
+			ls.wg.Add(1)
+			go func() {
+				defer ls.wg.Done()
+				defer ls.untrack(conn)
+				handle(conn)
+			}()
+		}
+	}()
+	return nil
+}
+
+// Close stops the listeners and waits for the pending requests
+func (ls *listeners) Close() error {
+	ls.closeOnce.Do(func() {
+		ls.mu.Lock()
+		ls.closed = true
+		for c := range ls.closers {
+			c.Close()
+		}
+		ls.mu.Unlock()
+		ls.wg.Wait()
+	})
+	return nil
+}
+
+// track keeps the closer to close it along with the server
+func (ls *listeners) track(c io.Closer) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if ls.closed {
+		c.Close()
+		return errors.New("server is closed")
+	}
+	ls.closers[c] = true
+	return nil
+}
+
+// untrack closes a closer that is no longer needed
+func (ls *listeners) untrack(c io.Closer) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	delete(ls.closers, c)
+	c.Close()
+}
+
+func (ls *listeners) isClosed() bool {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	return ls.closed
+}
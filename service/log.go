@@ -1,23 +1,154 @@
 package service
 
 import (
+	"fmt"
+	"io"
+	"time"
+
 	"golang.org/x/net/context"
 
 	pb "github.com/RomanosTrechlis/go-scribe/api"
 	"google.golang.org/grpc"
 )
 
-// Logger contains the stream channel
+// Entry is a batch of requests pushed to the stream.
+// When Done is not nil the sender waits on it
+// for the result of handling the batch.
+type Entry struct {
+	Batch pb.LogBatch
+	Ack   pb.Ack
+	Done  chan error
+	// Accepted, when not nil, is where the receiver reports whether
+	// it accepted the batch, before the batch is acknowledged
+	Accepted chan error
+}
+
+// Logger contains the stream channel.
+// Every request, even a single one, is pushed to the stream as a batch.
+//
+// Failures are reported with gRPC status codes: InvalidArgument for
+// malformed requests, Unavailable while shutting down, the code returned
+// by Admit for rejected requests and the code returned by Status for
+// failed writes or batches not accepted.
 type Logger struct {
-	Stream chan pb.LogRequest
+	Stream chan Entry
+	// Stop is closed when the stream stops being consumed
+	Stop chan struct{}
+	// Ack is the acknowledgement mode used
+	// for requests asking for the default one.
+	Ack pb.Ack
+	// Admit, when not nil, decides whether a batch is accepted before
+	// it is pushed, i.e. rejecting it while the disk is running out.
+	// It may drop requests of the batch, which carries the
+	// acknowledgement mode resolved, accepting the rest.
+	Admit func(b *pb.LogBatch) error
+	// Confirm is set when the receiver reports on Accepted whether it
+	// accepted every batch taken, i.e. after recording it to a spool.
+	// A batch not taken, because the context of the sender ended
+	// first, is never seen by the receiver.
+	Confirm bool
 }
 
 // Log is the ptotobuf service implementation
 func (l Logger) Log(ctx context.Context, in *pb.LogRequest) (*pb.LogResponse, error) {
-	l.Stream <- *in
+	if err := validate("", in); err != nil {
+		return nil, err
+	}
+	err := l.push(ctx, &pb.LogBatch{Entries: []*pb.LogRequest{in}}, in.GetAck())
+	if err != nil {
+		return nil, err
+	}
 	return &pb.LogResponse{Res: "true"}, nil
 }
 
+// LogStream is the client-streaming protobuf service implementation.
+// Every request received is pushed to the stream channel and when the
+// client closes the stream a summary with the number of requests is returned.
+func (l Logger) LogStream(stream pb.LogScribe_LogStreamServer) error {
+	var count int64
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&pb.LogStreamSummary{Count: count})
+		}
+		if err != nil {
+			return err
+		}
+		if err := validate("", in); err != nil {
+			return err
+		}
+		err = l.push(stream.Context(), &pb.LogBatch{Entries: []*pb.LogRequest{in}}, in.GetAck())
+		if err != nil {
+			return err
+		}
+		count++
+	}
+}
+
+// LogBatch is the protobuf service implementation for batched requests
+func (l Logger) LogBatch(ctx context.Context, in *pb.LogBatch) (*pb.LogBatchResponse, error) {
+	for i, e := range in.GetEntries() {
+		if err := validate(fmt.Sprintf("entries[%d].", i), e); err != nil {
+			return nil, err
+		}
+	}
+	err := l.push(ctx, in, in.GetAck())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.LogBatchResponse{Count: int64(len(in.GetEntries()))}, nil
+}
+
+// push sends the batch to the stream and, depending on the
+// acknowledgement mode, waits for the result of handling it.
+// The batch is left holding the requests admitted.
+func (l Logger) push(ctx context.Context, b *pb.LogBatch, ack pb.Ack) error {
+	if ack == pb.Ack_ACK_DEFAULT {
+		ack = l.Ack
+	}
+	b.Ack = ack
+	if l.Admit != nil {
+		if err := l.Admit(b); err != nil {
+			return err
+		}
+	}
+	e := Entry{Batch: *b, Ack: ack}
+	if ack >= pb.Ack_ACK_WRITTEN {
+		e.Done = make(chan error, 1)
+	}
+	if l.Confirm {
+		e.Accepted = make(chan error, 1)
+	}
+
+	select {
+	case l.Stream <- e:
+	case <-l.Stop:
+		return Unavailable("server is shutting down", 5*time.Second)
+	case <-ctx.Done():
+		return Status(ctx.Err())
+	}
+	if e.Accepted != nil {
+		select {
+		case err := <-e.Accepted:
+			if err != nil {
+				return Status(err)
+			}
+		case <-ctx.Done():
+			return Status(ctx.Err())
+		}
+	}
+	if e.Done == nil {
+		return nil
+	}
+
+	select {
+	case err := <-e.Done:
+		return Status(err)
+	case <-ctx.Done():
+		return Status(ctx.Err())
+	}
+}
+
 // GRPCService describes a method dealing with protobuf incoming requests
 type GRPCService interface {
 	serviceHandler(stop chan struct{}, s *grpc.Server)
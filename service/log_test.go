@@ -0,0 +1,156 @@
+package service
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLogAdmit(t *testing.T) {
+	stream := make(chan Entry, 1)
+	l := Logger{Stream: stream, Stop: make(chan struct{}), Admit: func(b *pb.LogBatch) error {
+		for _, e := range b.GetEntries() {
+			if e.GetSeverity() < pb.Severity_ERROR {
+				return ResourceExhausted("", "disk space is low")
+			}
+		}
+		return nil
+	}}
+
+	_, err := l.LogBatch(context.Background(), &pb.LogBatch{Entries: []*pb.LogRequest{
+		{Filename: "a", Severity: pb.Severity_ERROR},
+		{Filename: "a", Severity: pb.Severity_INFO},
+	}})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected code %v and got '%v'", codes.ResourceExhausted, err)
+	}
+	if len(stream) != 0 {
+		t.Fatalf("expected the rejected batch not to be pushed")
+	}
+
+	_, err = l.Log(context.Background(), &pb.LogRequest{Filename: "a", Severity: pb.Severity_FATAL})
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	if len(stream) != 1 {
+		t.Errorf("expected the admitted request to be pushed")
+	}
+}
+
+func TestLogConfirm(t *testing.T) {
+	stream := make(chan Entry)
+	l := Logger{Stream: stream, Stop: make(chan struct{}), Confirm: true}
+	go func() {
+		for e := range stream {
+			if e.Batch.GetEntries()[0].GetFilename() == "full" {
+				e.Accepted <- ResourceExhausted("", "no space left on device")
+				continue
+			}
+			e.Accepted <- nil
+		}
+	}()
+	defer close(stream)
+
+	_, err := l.Log(context.Background(), &pb.LogRequest{Filename: "full"})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected code %v and got '%v'", codes.ResourceExhausted, err)
+	}
+	if _, err := l.Log(context.Background(), &pb.LogRequest{Filename: "a"}); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+}
+
+func TestLogCancelledWhileBlocked(t *testing.T) {
+	// nobody takes from the stream
+	stream := make(chan Entry)
+	l := Logger{Stream: stream, Stop: make(chan struct{}), Confirm: true}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	_, err := l.Log(ctx, &pb.LogRequest{Filename: "a"})
+	if status.Code(err) != codes.Canceled {
+		t.Fatalf("expected code %v and got '%v'", codes.Canceled, err)
+	}
+	select {
+	case <-stream:
+		t.Errorf("expected the cancelled batch never to reach the receiver")
+	default:
+	}
+}
+
+// serveLogger serves the logger on a local port and returns a client of it
+func serveLogger(t *testing.T, l Logger) (pb.LogScribeClient, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	pb.RegisterLogScribeServer(srv, l)
+	go srv.Serve(lis)
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	return pb.NewLogScribeClient(conn), func() {
+		conn.Close()
+		srv.Stop()
+	}
+}
+
+func TestLogStream(t *testing.T) {
+	stream := make(chan Entry, 10)
+	l := Logger{Stream: stream, Stop: make(chan struct{}), Admit: func(b *pb.LogBatch) error {
+		if b.GetEntries()[0].GetFilename() == "full" {
+			return ResourceExhausted("", "disk space is low")
+		}
+		return nil
+	}}
+	c, stop := serveLogger(t, l)
+	defer stop()
+
+	s, err := c.LogStream(context.Background())
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := s.Send(&pb.LogRequest{Filename: "a", Line: "line"}); err != nil {
+			t.Fatalf("expected nil error and got '%v'", err)
+		}
+	}
+	summary, err := s.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	if summary.GetCount() != 5 || len(stream) != 5 {
+		t.Errorf("expected 5 requests and got %d, %d pushed", summary.GetCount(), len(stream))
+	}
+
+	var tests = []struct {
+		req  *pb.LogRequest
+		code codes.Code
+	}{
+		{&pb.LogRequest{Filename: "../a", Line: "line"}, codes.InvalidArgument},
+		{&pb.LogRequest{Filename: "full", Line: "line"}, codes.ResourceExhausted},
+	}
+	for _, tt := range tests {
+		s, err := c.LogStream(context.Background())
+		if err != nil {
+			t.Fatalf("expected nil error and got '%v'", err)
+		}
+		s.Send(&pb.LogRequest{Filename: "a", Line: "line"})
+		s.Send(tt.req)
+		// the rejected request ends the stream with its code
+		if _, err := s.CloseAndRecv(); status.Code(err) != tt.code {
+			t.Errorf("expected code %v for %v and got '%v'", tt.code, tt.req, err)
+		}
+	}
+}
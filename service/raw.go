@@ -0,0 +1,143 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"strings"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	p "github.com/RomanosTrechlis/go-scribe/internal/util/format/print"
+	"golang.org/x/net/context"
+)
+
+const (
+	// maxRawLineSize is the biggest line accepted by the raw listeners
+	maxRawLineSize = 1024 * 1024
+	// maxRawBatch is the maximum number of lines pushed as one batch
+	maxRawBatch = 1000
+)
+
+// RawServer receives plain text over TCP and Unix domain sockets.
+// The first line of a connection is its header, the path and
+// the filename of the file, i.e. web/access, and every line
+// after it gets written to that file, i.e.
+//
+//	(echo web/access; tail -f access.log) | nc localhost 7070
+//
+// Failures are written back to the connection as a line starting
+// with "ERR" before closing it.
+type RawServer struct {
+	listeners
+	logger Logger
+}
+
+// NewRawServer creates a raw server pushing to the logger's stream
+func NewRawServer(l Logger) *RawServer {
+	return &RawServer{listeners: newListeners(), logger: l}
+}
+
+// ListenTCP receives the lines sent over the connections to addr
+func (s *RawServer) ListenTCP(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for raw lines on tcp %s: %v", addr, err)
+	}
+	return s.serve(lis, "raw tcp", s.handle)
+}
+
+// ListenUnix receives the lines sent over the connections to
+// the Unix domain socket, replacing a stale socket file.
+func (s *RawServer) ListenUnix(file string) error {
+	if info, err := os.Stat(file); err == nil && info.Mode()&os.ModeSocket != 0 {
+		os.Remove(file)
+	}
+	lis, err := net.Listen("unix", file)
+	if err != nil {
+		return fmt.Errorf("failed to listen for raw lines on unix socket %s: %v", file, err)
+	}
+	return s.serve(lis, "raw unix", s.handle)
+}
+
+// handle reads the header and then pushes the lines of the connection,
+// in batches of the lines that have already arrived.
+func (s *RawServer) handle(conn net.Conn) {
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), maxRawLineSize)
+
+	if !scanner.Scan() {
+		s.fail(conn, scanner.Err())
+		return
+	}
+	dir, filename := parseHeader(scanner.Text())
+	template := &pb.LogRequest{Path: dir, Filename: filename}
+	if err := validate("header.", template); err != nil {
+		s.fail(conn, err)
+		return
+	}
+
+	// the lines are read on their own goroutine, so that the lines
+	// arriving while a batch is pushed are waiting for the next one
+	lines := make(chan string, maxRawBatch)
+	done := make(chan struct{})
+	defer close(done)
+	var readErr error
+	go func() {
+		defer close(lines)
+		for scanner.Scan() {
+			select {
+			case lines <- strings.TrimSuffix(scanner.Text(), "\r"):
+			case <-done:
+				return
+			}
+		}
+		readErr = scanner.Err()
+	}()
+
+	for line := range lines {
+		b := pb.LogBatch{Entries: []*pb.LogRequest{{Path: dir, Filename: filename, Line: line}}}
+		// taking only the lines already read keeps the lines of slow
+		// senders from waiting, while fast senders get their lines batched
+	batch:
+		for len(b.Entries) < maxRawBatch {
+			select {
+			case line, ok := <-lines:
+				if !ok {
+					break batch
+				}
+				b.Entries = append(b.Entries, &pb.LogRequest{Path: dir, Filename: filename, Line: line})
+			default:
+				break batch
+			}
+		}
+		if err := s.logger.push(context.Background(), &b, pb.Ack_ACK_DEFAULT); err != nil {
+			s.fail(conn, err)
+			return
+		}
+	}
+	if readErr != nil {
+		s.fail(conn, readErr)
+	}
+}
+
+// fail writes the error back to the connection
+func (s *RawServer) fail(conn net.Conn, err error) {
+	if err == nil {
+		err = InvalidArgument("header", "must not be empty")
+	}
+	if s.isClosed() {
+		return
+	}
+	msg := Status(err).Error()
+	p.Print(fmt.Sprintf("raw connection from %s failed: %s", conn.RemoteAddr(), msg))
+	fmt.Fprintf(conn, "ERR %s\n", msg)
+}
+
+// parseHeader splits the header line to the path and the filename
+func parseHeader(header string) (string, string) {
+	header = strings.TrimSpace(header)
+	dir, filename := path.Split(header)
+	return strings.Trim(dir, "/"), filename
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseHeader(t *testing.T) {
+	var tc = []struct {
+		header, path, filename string
+	}{
+		{"app", "", "app"},
+		{"web/api/access\r", "web/api", "access"},
+		{"/web/access", "web", "access"},
+		{"web/", "web", ""},
+	}
+	for _, tt := range tc {
+		dir, filename := parseHeader(tt.header)
+		if dir != tt.path || filename != tt.filename {
+			t.Errorf("for '%s' expected '%s' and '%s', got '%s' and '%s'",
+				tt.header, tt.path, tt.filename, dir, filename)
+		}
+	}
+}
+
+func TestRawServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raw")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "scribe.sock")
+
+	stream := make(chan Entry, 10)
+	s := NewRawServer(Logger{Stream: stream, Stop: make(chan struct{})})
+	defer s.Close()
+	if err := s.ListenTCP("127.0.0.1:0"); err != nil {
+		t.Fatalf("failed to listen on tcp: %v", err)
+	}
+	if err := s.ListenUnix(socket); err != nil {
+		t.Fatalf("failed to listen on unix socket: %v", err)
+	}
+	var tcp net.Addr
+	s.mu.Lock()
+	for c := range s.closers {
+		if l, ok := c.(*net.TCPListener); ok {
+			tcp = l.Addr()
+		}
+	}
+	s.mu.Unlock()
+
+	for _, addr := range []net.Addr{tcp, &net.UnixAddr{Name: socket, Net: "unix"}} {
+		conn, err := net.Dial(addr.Network(), addr.String())
+		if err != nil {
+			t.Fatalf("failed to dial %v: %v", addr, err)
+		}
+		fmt.Fprintf(conn, "web/%s\n1\n2\n3\n", addr.Network())
+		conn.Close()
+
+		lines := make([]string, 0)
+		for len(lines) < 3 {
+			select {
+			case e := <-stream:
+				for _, r := range e.Batch.GetEntries() {
+					if r.GetPath() != "web" || r.GetFilename() != addr.Network() {
+						t.Errorf("expected file web/%s and got %s/%s", addr.Network(), r.GetPath(), r.GetFilename())
+					}
+					lines = append(lines, r.GetLine())
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("expected lines over %s and got %v", addr.Network(), lines)
+			}
+		}
+		if strings.Join(lines, ",") != "1,2,3" {
+			t.Errorf("expected lines 1,2,3 and got %v", lines)
+		}
+	}
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+	fmt.Fprint(conn, "web/\nline\n")
+	reply, _ := bufio.NewReader(conn).ReadString('\n')
+	if !strings.HasPrefix(reply, "ERR ") || !strings.Contains(reply, "header.filename") {
+		t.Errorf("expected an error about the header and got '%s'", reply)
+	}
+}
+
+func TestRawBatches(t *testing.T) {
+	// nothing takes from the stream until every line has arrived
+	stream := make(chan Entry)
+	s := NewRawServer(Logger{Stream: stream, Stop: make(chan struct{})})
+	defer s.Close()
+	if err := s.ListenTCP("127.0.0.1:0"); err != nil {
+		t.Fatalf("failed to listen on tcp: %v", err)
+	}
+	var addr net.Addr
+	s.mu.Lock()
+	for c := range s.closers {
+		addr = c.(net.Listener).Addr()
+	}
+	s.mu.Unlock()
+
+	conn, err := net.Dial("tcp", addr.String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	fmt.Fprint(conn, "app\n")
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(conn, "%d\n", i)
+	}
+	conn.Close()
+	time.Sleep(50 * time.Millisecond)
+
+	lines, batches := 0, 0
+	for lines < 100 {
+		select {
+		case e := <-stream:
+			for _, r := range e.Batch.GetEntries() {
+				if r.GetLine() != fmt.Sprint(lines) {
+					t.Fatalf("expected line %d and got %s", lines, r.GetLine())
+				}
+				lines++
+			}
+			batches++
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected 100 lines and got %d", lines)
+		}
+	}
+	// the first line, or lines, and then all the lines waiting for it
+	if batches > 2 {
+		t.Errorf("expected the lines arrived to be batched and got %d batches", batches)
+	}
+}
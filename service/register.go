@@ -8,14 +8,22 @@ import (
 	"golang.org/x/net/context"
 )
 
-// Register holds the subscribers
+// Register adds the subscribers
 type Register struct {
-	Subscribers map[string]string
+	// Subscribe adds the subscriber with the id at addr
+	// and must be safe to call concurrently.
+	Subscribe func(id, addr string)
 }
 
 // Register implements the corresponding protobuf service
 func (r *Register) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
-	r.Subscribers[req.GetId()] = req.GetAddr()
+	if req.GetId() == "" {
+		return nil, InvalidArgument("id", "must not be empty")
+	}
+	if req.GetAddr() == "" {
+		return nil, InvalidArgument("addr", "must not be empty")
+	}
+	r.Subscribe(req.GetId(), req.GetAddr())
 	p.Print(fmt.Sprintf("Registering streamer %s from %s", req.GetId(), req.GetAddr()))
 	return &pb.RegisterResponse{Res: "Success"}, nil
 }
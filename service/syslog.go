@@ -0,0 +1,392 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	p "github.com/RomanosTrechlis/go-scribe/internal/util/format/print"
+	"golang.org/x/net/context"
+)
+
+const (
+	// DefaultSyslogRule names the files after the app-name of the messages
+	DefaultSyslogRule = "{app}"
+	// maxSyslogSize is the biggest syslog message accepted
+	maxSyslogSize = 64 * 1024
+)
+
+// facilities are the names of the syslog facilities by their code
+var facilities = []string{"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+	"uucp", "cron", "authpriv", "ftp", "ntp", "security", "console", "solaris-cron",
+	"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7"}
+
+// SyslogServer receives syslog messages, RFC 5424 or RFC 3164,
+// over UDP and TCP and pushes them to the stream of the logger.
+//
+// The file of a message is given by the rule, a template where
+// {app}, {host} and {facility} get replaced by the values of the message.
+// An app-name missing from the message falls back to the hostname,
+// and a hostname missing falls back to the address of the sender.
+// A rule containing slashes defines the path of the file too.
+type SyslogServer struct {
+	listeners
+	logger Logger
+	rule   string
+}
+
+// NewSyslogServer creates a syslog server pushing to the logger's stream
+func NewSyslogServer(l Logger, rule string) *SyslogServer {
+	if rule == "" {
+		rule = DefaultSyslogRule
+	}
+	return &SyslogServer{listeners: newListeners(), logger: l, rule: rule}
+}
+
+// ListenUDP receives a message from every datagram sent to addr
+func (s *SyslogServer) ListenUDP(addr string) error {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for syslog on udp %s: %v", addr, err)
+	}
+	if err := s.track(conn); err != nil {
+		return err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		buf := make([]byte, maxSyslogSize)
+		for {
+			n, from, err := conn.ReadFrom(buf)
+			if err != nil {
+				if !s.isClosed() {
+					p.Print(fmt.Sprintf("syslog udp listener stopped: %v", err))
+				}
+				return
+			}
+			s.handle(string(buf[:n]), host(from))
+		}
+	}()
+	return nil
+}
+
+// ListenTCP receives the messages sent over the connections to addr,
+// framed either by octet counting or by newlines (RFC 6587).
+func (s *SyslogServer) ListenTCP(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen for syslog on tcp %s: %v", addr, err)
+	}
+	return s.serve(lis, "syslog tcp", func(conn net.Conn) {
+		s.readFrames(conn, host(conn.RemoteAddr()))
+	})
+}
+
+// readFrames reads the messages of a TCP connection until it closes
+func (s *SyslogServer) readFrames(conn io.Reader, from string) {
+	r := bufio.NewReaderSize(conn, maxSyslogSize)
+	for {
+		msg, err := readFrame(r)
+		if msg != "" {
+			s.handle(msg, from)
+		}
+		if err != nil {
+			if err != io.EOF && !s.isClosed() {
+				p.Print(fmt.Sprintf("syslog connection from %s failed: %v", from, err))
+			}
+			return
+		}
+	}
+}
+
+// readFrame reads a message framed by octet counting,
+// i.e. "11 <13>message", or else terminated by a newline.
+func readFrame(r *bufio.Reader) (string, error) {
+	b, err := r.Peek(1)
+	if err != nil {
+		return "", err
+	}
+	if b[0] < '1' || b[0] > '9' {
+		line, err := r.ReadString('\n')
+		return strings.TrimRight(line, "\r\n"), err
+	}
+
+	length, err := r.ReadString(' ')
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(length, " "))
+	if err != nil || n > maxSyslogSize {
+		return "", fmt.Errorf("invalid frame length '%s'", strings.TrimSuffix(length, " "))
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(msg), "\r\n"), nil
+}
+
+// handle parses the message and pushes it to the stream
+func (s *SyslogServer) handle(msg, from string) {
+	msg = strings.TrimRight(msg, "\r\n\x00")
+	if msg == "" {
+		return
+	}
+	m := parseSyslog(msg, time.Now())
+	if m.host == "" {
+		m.host = from
+	}
+	r := m.request(s.rule)
+	if err := validate("", r); err != nil {
+		p.Print(fmt.Sprintf("dropped syslog message from %s: %v", from, err))
+		return
+	}
+	// syslog senders can't get a reply, so there is nothing to wait for
+	err := s.logger.push(context.Background(), &pb.LogBatch{Entries: []*pb.LogRequest{r}}, pb.Ack_ACK_RECEIVED)
+	if err != nil {
+		p.Print(fmt.Sprintf("failed to push syslog message from %s: %v", from, err))
+	}
+}
+
+// syslogMessage holds the parts of a syslog message
+type syslogMessage struct {
+	priority int
+	time     time.Time
+	host     string
+	app      string
+	procID   string
+	msgID    string
+	fields   map[string]string
+	msg      string
+}
+
+// parseSyslog parses an RFC 5424 or an RFC 3164 message. Parts that can't be
+// parsed are left empty and the rest of the message is kept as the line.
+func parseSyslog(msg string, now time.Time) syslogMessage {
+	// the priority of messages without one is user.notice
+	m := syslogMessage{priority: 13, fields: make(map[string]string)}
+	if strings.HasPrefix(msg, "<") {
+		if end := strings.IndexByte(msg, '>'); end > 1 && end < 5 {
+			if pri, err := strconv.Atoi(msg[1:end]); err == nil && pri < 192 {
+				m.priority = pri
+				msg = msg[end+1:]
+			}
+		}
+	}
+
+	if strings.HasPrefix(msg, "1 ") {
+		m.parse5424(msg[2:])
+	} else {
+		m.parse3164(msg, now)
+	}
+	if m.procID != "" {
+		m.fields["procid"] = m.procID
+	}
+	if m.msgID != "" {
+		m.fields["msgid"] = m.msgID
+	}
+	m.fields["facility"] = facilities[m.priority/8]
+	return m
+}
+
+// parse5424 parses the message after "<PRI>1 ",
+// TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA [MSG]
+func (m *syslogMessage) parse5424(msg string) {
+	var parts [5]string
+	for i := range parts {
+		parts[i], msg = token(msg)
+	}
+	if t, err := time.Parse(time.RFC3339Nano, parts[0]); err == nil {
+		m.time = t
+	}
+	m.host, m.app, m.procID, m.msgID = nilValue(parts[1]), nilValue(parts[2]), nilValue(parts[3]), nilValue(parts[4])
+
+	if strings.HasPrefix(msg, "-") {
+		msg = msg[1:]
+	} else {
+		msg = m.parseStructuredData(msg)
+	}
+	msg = strings.TrimPrefix(msg, " ")
+	m.msg = strings.TrimPrefix(msg, "\xEF\xBB\xBF")
+}
+
+// parseStructuredData parses the elements, [id name="value" ...], as fields
+// named id.name and returns the rest of the message.
+func (m *syslogMessage) parseStructuredData(msg string) string {
+	for strings.HasPrefix(msg, "[") {
+		end := strings.IndexAny(msg, " ]")
+		if end < 0 {
+			return msg
+		}
+		id := msg[1:end]
+		msg = msg[end:]
+		for strings.HasPrefix(msg, " ") {
+			msg = strings.TrimLeft(msg, " ")
+			eq := strings.Index(msg, `="`)
+			if eq < 0 {
+				return msg
+			}
+			name := msg[:eq]
+			value, rest, ok := quoted(msg[eq+2:])
+			if !ok {
+				return msg
+			}
+			m.fields[id+"."+name] = value
+			msg = rest
+		}
+		if !strings.HasPrefix(msg, "]") {
+			return msg
+		}
+		msg = msg[1:]
+	}
+	return msg
+}
+
+// parse3164 parses the message after "<PRI>",
+// TIMESTAMP HOSTNAME TAG[PID]: MSG
+func (m *syslogMessage) parse3164(msg string, now time.Time) {
+	if len(msg) < len(time.Stamp) {
+		m.msg = msg
+		return
+	}
+	t, err := time.ParseInLocation(time.Stamp, msg[:len(time.Stamp)], now.Location())
+	if err != nil {
+		m.msg = msg
+		return
+	}
+	// the timestamp has no year, the message was sent at most a day ahead
+	t = t.AddDate(now.Year(), 0, 0)
+	if t.After(now.AddDate(0, 0, 1)) {
+		t = t.AddDate(-1, 0, 0)
+	}
+	m.time = t
+
+	m.host, msg = token(strings.TrimPrefix(msg[len(time.Stamp):], " "))
+	end := strings.IndexAny(msg, "[: ")
+	if end <= 0 || end > 48 {
+		m.msg = msg
+		return
+	}
+	m.app = msg[:end]
+	msg = msg[end:]
+	if strings.HasPrefix(msg, "[") {
+		if pid := strings.IndexByte(msg, ']'); pid > 0 {
+			m.procID = msg[1:pid]
+			msg = msg[pid+1:]
+		}
+	}
+	msg = strings.TrimPrefix(msg, ":")
+	m.msg = strings.TrimPrefix(msg, " ")
+}
+
+// request maps the message to a request with its file named by the rule
+func (m syslogMessage) request(rule string) *pb.LogRequest {
+	app, host := m.app, m.host
+	if host == "" {
+		host = "syslog"
+	}
+	if app == "" {
+		app = host
+	}
+	name := strings.NewReplacer(
+		"{app}", sanitize(app),
+		"{host}", sanitize(host),
+		"{facility}", m.fields["facility"],
+	).Replace(rule)
+	path, filename := filepath.Split(filepath.Clean("/" + name))
+
+	r := &pb.LogRequest{
+		Filename: filename,
+		Path:     strings.Trim(path, "/"),
+		Line:     m.msg,
+		Severity: severity(m.priority % 8),
+		Host:     m.host,
+		Fields:   m.fields,
+	}
+	if !m.time.IsZero() {
+		r.Timestamp = m.time.UnixNano()
+	}
+	return r
+}
+
+// severity maps a syslog severity to the severity of a request
+func severity(s int) pb.Severity {
+	switch {
+	case s <= 2:
+		// emergency, alert and critical
+		return pb.Severity_FATAL
+	case s == 3:
+		return pb.Severity_ERROR
+	case s == 4:
+		return pb.Severity_WARNING
+	case s <= 6:
+		// notice and informational
+		return pb.Severity_INFO
+	default:
+		return pb.Severity_DEBUG
+	}
+}
+
+// token splits the message at the first space
+func token(msg string) (string, string) {
+	i := strings.IndexByte(msg, ' ')
+	if i < 0 {
+		return msg, ""
+	}
+	return msg[:i], msg[i+1:]
+}
+
+// nilValue returns empty for the nil value, "-", of RFC 5424
+func nilValue(v string) string {
+	if v == "-" {
+		return ""
+	}
+	return v
+}
+
+// quoted reads a value up to its closing quote, unescaping \", \\ and \]
+func quoted(msg string) (string, string, bool) {
+	var b strings.Builder
+	for i := 0; i < len(msg); i++ {
+		switch msg[i] {
+		case '\\':
+			if i+1 < len(msg) && strings.IndexByte(`"\]`, msg[i+1]) >= 0 {
+				i++
+			}
+			b.WriteByte(msg[i])
+		case '"':
+			return b.String(), msg[i+1:], true
+		default:
+			b.WriteByte(msg[i])
+		}
+	}
+	return "", msg, false
+}
+
+// sanitize keeps the value from escaping the file name,
+// replacing the characters a name can't contain with '_'
+func sanitize(v string) string {
+	v = strings.Map(func(c rune) rune {
+		if validChar(c) {
+			return c
+		}
+		return '_'
+	}, v)
+	return strings.Replace(v, "..", "_", -1)
+}
+
+// host returns the host of the address
+func host(addr net.Addr) string {
+	h, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return h
+}
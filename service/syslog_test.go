@@ -0,0 +1,131 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+)
+
+func TestParseSyslog(t *testing.T) {
+	now := time.Date(2018, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	var tc = []struct {
+		name     string
+		msg      string
+		rule     string
+		path     string
+		filename string
+		line     string
+		severity pb.Severity
+		host     string
+		fields   map[string]string
+	}{
+		{"rfc5424", `<165>1 2018-09-01T10:00:00.003Z web01 nginx 42 ID47 [req@1 id="a\"b" ms="3"] started`,
+			"{app}", "", "nginx", "started", pb.Severity_INFO, "web01",
+			map[string]string{"procid": "42", "msgid": "ID47", "req@1.id": `a"b`, "req@1.ms": "3", "facility": "local4"}},
+		{"rfc5424 nil values", "<11>1 - - - - - - failed", "{app}", "", "from", "failed", pb.Severity_ERROR, "",
+			map[string]string{"facility": "user"}},
+		{"rfc3164", "<34>Sep  1 10:00:00 router sshd[99]: login failed", "{host}/{app}", "router", "sshd",
+			"login failed", pb.Severity_FATAL, "router", map[string]string{"procid": "99", "facility": "auth"}},
+		{"no priority", "just a line", "{facility}", "", "user", "just a line", pb.Severity_INFO, "",
+			map[string]string{"facility": "user"}},
+		{"escaping app", "<15>1 - h ../etc - - - x", "{app}", "", "__etc", "x", pb.Severity_DEBUG, "h",
+			map[string]string{"facility": "user"}},
+		{"ipv6 host", "<15>1 - fe80::1 - - - - x", "{host}", "", "fe80__1", "x", pb.Severity_DEBUG, "fe80::1",
+			map[string]string{"facility": "user"}},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			m := parseSyslog(tt.msg, now)
+			if m.host == "" && m.app == "" {
+				m.host = "from"
+			}
+			r := m.request(tt.rule)
+			if r.GetPath() != tt.path || r.GetFilename() != tt.filename {
+				t.Errorf("expected file '%s/%s' and got '%s/%s'", tt.path, tt.filename, r.GetPath(), r.GetFilename())
+			}
+			if r.GetLine() != tt.line {
+				t.Errorf("expected line '%s' and got '%s'", tt.line, r.GetLine())
+			}
+			if r.GetSeverity() != tt.severity {
+				t.Errorf("expected severity %v and got %v", tt.severity, r.GetSeverity())
+			}
+			if tt.host != "" && r.GetHost() != tt.host {
+				t.Errorf("expected host '%s' and got '%s'", tt.host, r.GetHost())
+			}
+			if fmt.Sprint(r.GetFields()) != fmt.Sprint(tt.fields) {
+				t.Errorf("expected fields %v and got %v", tt.fields, r.GetFields())
+			}
+		})
+	}
+}
+
+func TestParseSyslogTime(t *testing.T) {
+	now := time.Date(2018, 1, 1, 1, 0, 0, 0, time.UTC)
+	m := parseSyslog("<13>Dec 31 23:59:00 host app: x", now)
+	if exp := time.Date(2017, 12, 31, 23, 59, 0, 0, time.UTC); !m.time.Equal(exp) {
+		t.Errorf("expected time %v and got %v", exp, m.time)
+	}
+}
+
+func TestReadFrame(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("9 <13>a b c<13>line\n12 <13>new\nline"))
+	exp := []string{"<13>a b c", "<13>line", "<13>new\nline"}
+	for _, e := range exp {
+		msg, err := readFrame(r)
+		if err != nil {
+			t.Fatalf("expected nil error and got '%v'", err)
+		}
+		if msg != e {
+			t.Errorf("expected '%s' and got '%s'", e, msg)
+		}
+	}
+}
+
+func TestSyslogServer(t *testing.T) {
+	stream := make(chan Entry, 2)
+	s := NewSyslogServer(Logger{Stream: stream, Stop: make(chan struct{})}, "")
+	defer s.Close()
+	if err := s.ListenUDP("127.0.0.1:0"); err != nil {
+		t.Fatalf("failed to listen on udp: %v", err)
+	}
+	if err := s.ListenTCP("127.0.0.1:0"); err != nil {
+		t.Fatalf("failed to listen on tcp: %v", err)
+	}
+	var udp, tcp net.Addr
+	s.mu.Lock()
+	for c := range s.closers {
+		switch l := c.(type) {
+		case net.PacketConn:
+			udp = l.LocalAddr()
+		case net.Listener:
+			tcp = l.Addr()
+		}
+	}
+	s.mu.Unlock()
+
+	for _, addr := range []net.Addr{udp, tcp} {
+		conn, err := net.Dial(addr.Network(), addr.String())
+		if err != nil {
+			t.Fatalf("failed to dial %v: %v", addr, err)
+		}
+		fmt.Fprintf(conn, "<14>1 - host %s - - - hello\n", addr.Network())
+		conn.Close()
+
+		select {
+		case e := <-stream:
+			r := e.Batch.GetEntries()[0]
+			if r.GetFilename() != addr.Network() || r.GetLine() != "hello" {
+				t.Errorf("expected file %s with line hello and got %s with '%s'",
+					addr.Network(), r.GetFilename(), r.GetLine())
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected a message over %s", addr.Network())
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+)
+
+const (
+	// MaxPathLength is the length of the longest path of a request
+	MaxPathLength = 1024
+	// MaxFilenameLength is the length of the longest filename of a request,
+	// leaving room for the suffix of the rotated segments, as most
+	// file systems allow names of up to 255 bytes
+	MaxFilenameLength = 200
+)
+
+// ValidateFile checks the path and the filename of a request and returns
+// the canonical path, relative to the log root and without empty or "."
+// elements. The path must not leave the log root, and the filename and
+// the elements of the path must consist of letters, digits, '.', '_' and '-'.
+// The elements of the path must not start with '.', as the directories
+// starting with it are kept by the scribe, i.e. for the dead letters.
+// prefix is prepended to the name of the field in the error.
+func ValidateFile(prefix, path, filename string) (string, error) {
+	if filename == "" {
+		return "", InvalidArgument(prefix+"filename", "must not be empty")
+	}
+	if len(filename) > MaxFilenameLength {
+		return "", InvalidArgument(prefix+"filename", fmt.Sprintf("must not be longer than %d bytes", MaxFilenameLength))
+	}
+	if err := checkName(filename); err != nil {
+		return "", InvalidArgument(prefix+"filename", err.Error())
+	}
+
+	if len(path) > MaxPathLength {
+		return "", InvalidArgument(prefix+"path", fmt.Sprintf("must not be longer than %d bytes", MaxPathLength))
+	}
+	elems := make([]string, 0)
+	for _, e := range strings.Split(path, "/") {
+		if e == "" || e == "." {
+			continue
+		}
+		if err := checkName(e); err != nil {
+			return "", InvalidArgument(prefix+"path", err.Error())
+		}
+		if strings.HasPrefix(e, ".") {
+			return "", InvalidArgument(prefix+"path", fmt.Sprintf("'%s' must not start with '.'", e))
+		}
+		elems = append(elems, e)
+	}
+	return strings.Join(elems, "/"), nil
+}
+
+// checkName checks a filename or an element of a path
+func checkName(name string) error {
+	if name == "." || name == ".." {
+		return fmt.Errorf("must not leave the log root")
+	}
+	for _, c := range name {
+		if !validChar(c) {
+			return fmt.Errorf("must not contain %q", c)
+		}
+	}
+	return nil
+}
+
+func validChar(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '.' || c == '_' || c == '-'
+}
+
+// validate checks the fields every request must have,
+// replacing its path with the canonical one.
+// prefix is prepended to the name of the field in the error.
+func validate(prefix string, r *pb.LogRequest) error {
+	path, err := ValidateFile(prefix, r.GetPath(), r.GetFilename())
+	if err != nil {
+		return err
+	}
+	r.Path = path
+	return nil
+}
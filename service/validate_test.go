@@ -0,0 +1,86 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	"golang.org/x/net/context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidateFile(t *testing.T) {
+	var tc = []struct {
+		name     string
+		path     string
+		filename string
+		exp      string
+		field    string
+	}{
+		{"no path", "", "app", "", ""},
+		{"nested path", "web/nginx", "access", "web/nginx", ""},
+		{"canonical path", "/web//./nginx/", "access", "web/nginx", ""},
+		{"dots in names", "v1.2", "app.2018-09-01_x", "v1.2", ""},
+		{"no filename", "", "", "", "filename"},
+		{"traversal", "../../etc", "passwd", "", "path"},
+		{"traversal inside the root", "a/../b", "app", "", "path"},
+		{"dot dot filename", "", "..", "", "filename"},
+		{"reserved path", ".deadletter", "deadletter", "", "path"},
+		{"hidden directory", "web/.git", "app", "", "path"},
+		{"hidden filename", "web", ".app", "web", ""},
+		{"slash in filename", "", "a/b", "", "filename"},
+		{"backslash", `..\etc`, "app", "", "path"},
+		{"space", "", "my app", "", "filename"},
+		{"unicode", "λογ", "app", "", "path"},
+		{"nul", "", "app\x00", "", "filename"},
+		{"long filename", "", strings.Repeat("a", MaxFilenameLength+1), "", "filename"},
+		{"long path", strings.Repeat("a/", MaxPathLength/2+1), "app", "", "path"},
+	}
+	for _, tt := range tc {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := ValidateFile("entries[0].", tt.path, tt.filename)
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("expected nil error and got '%v'", err)
+				}
+				if path != tt.exp {
+					t.Errorf("expected path '%s' and got '%s'", tt.exp, path)
+				}
+				return
+			}
+			st := status.Convert(err)
+			if st.Code() != codes.InvalidArgument {
+				t.Fatalf("expected code %v and got %v", codes.InvalidArgument, st.Code())
+			}
+			br, ok := st.Details()[0].(*errdetails.BadRequest)
+			if !ok || br.GetFieldViolations()[0].GetField() != "entries[0]."+tt.field {
+				t.Errorf("expected violation of 'entries[0].%s' and got %v", tt.field, st.Details())
+			}
+		})
+	}
+}
+
+func TestLogValidates(t *testing.T) {
+	stream := make(chan Entry, 1)
+	l := Logger{Stream: stream, Stop: make(chan struct{})}
+
+	_, err := l.Log(context.Background(), &pb.LogRequest{Path: "../etc", Filename: "passwd", Line: "x"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected code %v and got %v", codes.InvalidArgument, status.Code(err))
+	}
+	if len(stream) != 0 {
+		t.Fatalf("expected the request not to be pushed")
+	}
+
+	// valid requests are pushed with their canonical path
+	_, err = l.Log(context.Background(), &pb.LogRequest{Path: "/web//nginx/", Filename: "access", Line: "x"})
+	if err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	e := <-stream
+	if got := e.Batch.Entries[0].GetPath(); got != "web/nginx" {
+		t.Errorf("expected path 'web/nginx' and got '%s'", got)
+	}
+}
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 type CertificateConfig struct {
 	Certificate          string `yaml:"certificate"`
 	PrivateKey           string `yaml:"private_key"`
@@ -15,6 +17,72 @@ type AgentConfig struct {
 	ProfilePort int    `yaml:"profile_port"`
 	LogPath     string `yaml:"log_path"`
 	LogFileSize int64  `yaml:"log_file_size"`
+	// Rotation is one of none, hourly, daily or a duration, i.e. 6h,
+	// rotating the files on time boundaries of the Timezone besides size
+	Rotation string `yaml:"rotation"`
+	Timezone string `yaml:"timezone"`
+	// SegmentCompression is gzip or none, compressing the rotated files
+	SegmentCompression string `yaml:"segment_compression"`
+	// RetentionAge, RetentionSegments and RetentionBytes, the budget of every
+	// path, limit the rotated files, which get deleted every RetentionInterval.
+	// Zero values disable their limit and RetentionDryRun only reports the files.
+	RetentionAge      time.Duration `yaml:"retention_age"`
+	RetentionSegments int           `yaml:"retention_segments"`
+	RetentionBytes    int64         `yaml:"retention_bytes"`
+	RetentionInterval time.Duration `yaml:"retention_interval"`
+	RetentionDryRun   bool          `yaml:"retention_dry_run"`
+	// DiskLow and DiskHigh are the free bytes of the log path below which
+	// the agent degrades and at which it recovers. While degraded, requests
+	// with severity lower than DiskKeep, all, debug, info, warning, error,
+	// fatal or none, are rejected. Zero DiskLow never degrades.
+	DiskLow  int64  `yaml:"disk_low"`
+	DiskHigh int64  `yaml:"disk_high"`
+	DiskKeep string `yaml:"disk_keep"`
+	// LogFormat is one of raw, json or text
+	LogFormat string `yaml:"log_format"`
+	// LogLayout is the layout used by the text format
+	LogLayout string `yaml:"log_layout"`
+	// AckMode is one of received, written or synced
+	AckMode string `yaml:"ack_mode"`
+	// FlushPolicy is one of line, size or interval. Unless it is line,
+	// the lines of every open file are kept in a buffer of BufferSize bytes
+	// until it is full or, for interval, until FlushInterval passes.
+	FlushPolicy   string        `yaml:"flush_policy"`
+	BufferSize    int64         `yaml:"buffer_size"`
+	FlushInterval time.Duration `yaml:"flush_interval"`
+	// SyncPolicy is one of never, interval or always,
+	// syncing the open files every SyncInterval for interval
+	SyncPolicy   string        `yaml:"sync_policy"`
+	SyncInterval time.Duration `yaml:"sync_interval"`
+	// SpoolPath is the directory of the write-ahead log recording the
+	// accepted requests until written, empty for none
+	SpoolPath string `yaml:"spool_path"`
+	// Workers is the number of workers writing the requests, the number
+	// of CPUs when zero, and QueueDepth the parts queued for every worker
+	Workers    int `yaml:"workers"`
+	QueueDepth int `yaml:"queue_depth"`
+	// OnError is one of skip, retry or deadletter, retrying
+	// the failed writes Retries times waiting Backoff, doubling
+	OnError string        `yaml:"on_error"`
+	Retries int           `yaml:"retries"`
+	Backoff time.Duration `yaml:"backoff"`
+	// HTTPPort is the port of the HTTP endpoint, zero disables it
+	HTTPPort int `yaml:"http_port"`
+	// SyslogUDP and SyslogTCP are the addresses receiving
+	// syslog messages, i.e. :514, empty disables them
+	SyslogUDP string `yaml:"syslog_udp"`
+	SyslogTCP string `yaml:"syslog_tcp"`
+	// SyslogRule names the files of the syslog messages,
+	// i.e. {host}/{app}, using {app}, {host} and {facility}
+	SyslogRule string `yaml:"syslog_rule"`
+	// RawTCP and RawUnix are the TCP address and the Unix domain
+	// socket receiving plain text lines, empty disables them
+	RawTCP  string `yaml:"raw_tcp"`
+	RawUnix string `yaml:"raw_unix"`
+	// Compression is the compressor, i.e. gzip, of the calls to the mediator,
+	// registering the scribe. Compressed requests are accepted regardless of
+	// it, and the replies to them are compressed the same way.
+	Compression string `yaml:"compression"`
 
 	CertificateConfig
 }
@@ -23,6 +91,11 @@ type MediatorConfig struct {
 	Port        int  `yaml:"port"`
 	Profile     bool `yaml:"profile"`
 	ProfilePort int  `yaml:"profile_port"`
+	// HTTPPort is the port of the HTTP endpoint, zero disables it
+	HTTPPort int `yaml:"http_port"`
+	// Compression is the compressor, i.e. gzip, of the requests
+	// forwarded to the scribes. Compressed requests are accepted regardless of it.
+	Compression string `yaml:"compression"`
 
 	CertificateConfig
 }
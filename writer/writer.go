@@ -5,28 +5,42 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"time"
 
 	pb "github.com/RomanosTrechlis/go-scribe/api"
+	"github.com/RomanosTrechlis/go-scribe/internal/util/gserver"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	// registers the gzip compressor used by WithCompression("gzip")
+	_ "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/status"
 )
 
 // RPCWriter implements Writer interface
 type RPCWriter struct {
 	conn     *grpc.ClientConn
 	filename string
+	// streaming is set when the writer sends its lines through
+	// a LogStream, which is nil until opened, or after it ended.
+	streaming bool
+	stream    pb.LogScribe_LogStreamClient
+	ack       pb.Ack
 }
 
 type builderImpl struct {
-	filename string
-	address  string
-	port     int
-	cert     string
-	key      string
-	ca       string
+	filename  string
+	address   string
+	port      int
+	cert      string
+	key       string
+	ca        string
+	streaming bool
+	ack       pb.Ack
+	// compression is the name of the compressor, i.e. gzip
+	compression string
 }
 
 // Builder interface holds the option methods
@@ -34,6 +48,9 @@ type builderImpl struct {
 type Builder interface {
 	WithFilename(filename string) Builder
 	WithSecurity(cert, key, ca string) Builder
+	WithStreaming() Builder
+	WithAck(ack pb.Ack) Builder
+	WithCompression(name string) Builder
 	Build() (*RPCWriter, error)
 }
 
@@ -60,6 +77,32 @@ func (b builderImpl) WithSecurity(cert, key, ca string) Builder {
 	return b
 }
 
+// WithStreaming makes the writer send its lines through a single
+// client-streaming LogStream instead of one Log call per line.
+// The stream is closed when the RPCWriter gets closed.
+func (b builderImpl) WithStreaming() Builder {
+	b.streaming = true
+	return b
+}
+
+// WithAck sets the acknowledgement mode of every request.
+// With pb.Ack_ACK_WRITTEN or pb.Ack_ACK_SYNCED a Write returns only
+// after scribe persisted the line, unless the writer is streaming
+// in which case a failed write is reported by a later Write or Close.
+func (b builderImpl) WithAck(ack pb.Ack) Builder {
+	b.ack = ack
+	return b
+}
+
+// WithCompression compresses every request with the named compressor.
+// gzip is always available, other compressors must be registered with
+// the encoding package of gRPC, and empty or none compresses nothing.
+// Scribes reply compressed as well.
+func (b builderImpl) WithCompression(name string) Builder {
+	b.compression = name
+	return b
+}
+
 // Build creates a new RPCWriter given the Builder parameters.
 func (b builderImpl) Build() (*RPCWriter, error) {
 	return newRPCWriter(b)
@@ -72,15 +115,42 @@ func newRPCWriter(b builderImpl) (*RPCWriter, error) {
 		port:    b.port,
 	}
 
-	conn, err := createConnection(b.cert, b.key, b.ca, s)
+	opts, err := gserver.DialCompression(b.compression)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := createConnection(b.cert, b.key, b.ca, s, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection to scribe: %v", err)
 	}
 
-	return &RPCWriter{
-		conn:     conn,
-		filename: b.filename,
-	}, nil
+	w := &RPCWriter{
+		conn:      conn,
+		filename:  b.filename,
+		ack:       b.ack,
+		streaming: b.streaming,
+	}
+	if !b.streaming {
+		return w, nil
+	}
+
+	if err := w.openStream(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// openStream opens the LogStream the lines are sent through
+func (w *RPCWriter) openStream() error {
+	c := pb.NewLogScribeClient(w.conn)
+	stream, err := c.LogStream(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to open stream to scribe: %v", err)
+	}
+	w.stream = stream
+	return nil
 }
 
 type scribe struct {
@@ -88,7 +158,7 @@ type scribe struct {
 	port    int
 }
 
-func createConnection(cert, key, ca string, sc scribe) (*grpc.ClientConn, error) {
+func createConnection(cert, key, ca string, sc scribe, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	var conn *grpc.ClientConn
 	server := fmt.Sprintf("%s:%d", sc.address, sc.port)
 	if cert != "" && key != "" && ca != "" {
@@ -118,18 +188,18 @@ func createConnection(cert, key, ca string, sc scribe) (*grpc.ClientConn, error)
 		})
 
 		// Create a connection with the TLS credentials
-		conn, err = grpc.Dial(server,
+		conn, err = grpc.Dial(server, append(opts,
 			grpc.WithTransportCredentials(creds),
-			grpc.WithTimeout(1*time.Second))
+			grpc.WithTimeout(1*time.Second))...)
 		if err != nil {
 			return nil, fmt.Errorf("did not connect: %v", err)
 		}
 		return conn, nil
 	}
 	var err error
-	conn, err = grpc.Dial(server,
+	conn, err = grpc.Dial(server, append(opts,
 		grpc.WithInsecure(),
-		grpc.WithTimeout(1*time.Second))
+		grpc.WithTimeout(1*time.Second))...)
 	if err != nil {
 		return nil, fmt.Errorf("did not connect: %v", err)
 	}
@@ -138,23 +208,78 @@ func createConnection(cert, key, ca string, sc scribe) (*grpc.ClientConn, error)
 
 // Write implements the Write method of Writer interface.
 // Inside this method there is a call to scribe.
+// A returned error carries the gRPC status reported by scribe,
+// i.e. codes.InvalidArgument or codes.Unavailable.
+// When streaming, scribe ends the stream rejecting a line, so the
+// next Write returns the error of that line, along with n equal to
+// len(p) as its own line is sent again through a new stream.
 func (w *RPCWriter) Write(p []byte) (n int, err error) {
 	n = len(p)
-	c := pb.NewLogScribeClient(w.conn)
 	req := &pb.LogRequest{
 		Filename: w.filename,
 		Line:     string(p),
+		Ack:      w.ack,
 	}
-	r, err := c.Log(context.Background(), req)
-	if err != nil {
-		return 0, fmt.Errorf("failled to write bytes: %v", err)
+	if w.streaming {
+		if w.stream == nil {
+			if err := w.openStream(); err != nil {
+				return 0, err
+			}
+		}
+		err = w.stream.Send(req)
+		if err != io.EOF {
+			if err != nil {
+				// the stream is broken, the next Write opens a new one
+				w.stream = nil
+				return 0, wrap(err, "failed to write bytes")
+			}
+			return n, nil
+		}
+		// the server ended the stream, the actual error of the earlier
+		// line is returned by CloseAndRecv, and never received this line
+		_, ended := w.stream.CloseAndRecv()
+		w.stream = nil
+		if err := w.openStream(); err != nil {
+			// the next Write tries to open it again
+			return 0, err
+		}
+		if err := w.stream.Send(req); err != nil {
+			return 0, wrap(err, "failed to write bytes")
+		}
+		if ended != nil {
+			return n, wrap(ended, "failed to write an earlier line")
+		}
+		return n, nil
 	}
-	if r.GetRes() != "true" {
-		return 0, fmt.Errorf("failled to write bytes: %v", err)
+
+	c := pb.NewLogScribeClient(w.conn)
+	_, err = c.Log(context.Background(), req)
+	if err != nil {
+		return 0, wrap(err, "failed to write bytes")
 	}
 	return n, nil
 }
 
+// wrap adds msg to the error keeping its gRPC status code and details,
+// so that callers can still inspect them with status.Code and status.Convert.
+func wrap(err error, msg string) error {
+	st := status.Convert(err).Proto()
+	st.Message = msg + ": " + st.Message
+	return status.ErrorProto(st)
+}
+
+// Close closes the stream, if any, and the connection to scribe.
+func (w *RPCWriter) Close() error {
+	if w.stream != nil {
+		_, err := w.stream.CloseAndRecv()
+		if err != nil {
+			w.conn.Close()
+			return wrap(err, "failed to close stream")
+		}
+	}
+	return w.conn.Close()
+}
+
 // NewLogger creates a *Logger with default prefix and flags and a new RPCWriter
 func NewLogger(filename, address string, port int, cert, key, ca string) (*log.Logger, error) {
 	w, err := NewBuilder(address, port).
@@ -0,0 +1,79 @@
+package writer
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/RomanosTrechlis/go-scribe/api"
+	"github.com/RomanosTrechlis/go-scribe/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStreamReopen(t *testing.T) {
+	stream := make(chan service.Entry, 10)
+	l := service.Logger{Stream: stream, Stop: make(chan struct{}), Admit: func(b *pb.LogBatch) error {
+		if b.GetEntries()[0].GetLine() == "bad" {
+			return service.ResourceExhausted("", "disk space is low")
+		}
+		return nil
+	}}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	pb.RegisterLogScribeServer(srv, l)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	// the second stream fails to open
+	opened := 0
+	fail := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if opened++; opened == 2 {
+			return nil, status.Error(codes.Unavailable, "no stream")
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure(), grpc.WithStreamInterceptor(fail))
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	w := &RPCWriter{conn: conn, filename: "app", streaming: true}
+	if err := w.openStream(); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+	defer w.Close()
+
+	for _, line := range []string{"a", "bad"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("expected nil error and got '%v'", err)
+		}
+	}
+	// the scribe ends the stream rejecting bad
+	time.Sleep(50 * time.Millisecond)
+	if _, err := w.Write([]byte("b")); err == nil {
+		t.Fatalf("expected error opening the stream again")
+	}
+	if _, err := w.Write([]byte("c")); err != nil {
+		t.Fatalf("expected nil error and got '%v'", err)
+	}
+
+	lines := make([]string, 0)
+	for len(lines) < 2 {
+		select {
+		case e := <-stream:
+			lines = append(lines, e.Batch.GetEntries()[0].GetLine())
+		case <-time.After(time.Second):
+			t.Fatalf("expected lines a,c and got %v", lines)
+		}
+	}
+	if strings.Join(lines, ",") != "a,c" {
+		t.Errorf("expected lines a,c and got %v", lines)
+	}
+}